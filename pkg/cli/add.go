@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/jiyeol-lee/gcli/pkg/gcal"
+	"github.com/jiyeol-lee/gcli/pkg/util"
+)
+
+var defaultEventDuration = "30m"
+
+func newAddCommand() *Command {
+	cmd := &Command{
+		Name:  "add",
+		Args:  "<summary> (--at <time> | --date <date>) [flags]",
+		Short: "Create an event",
+		Long: "Create an event.\n\n" +
+			"Times are RFC3339 or an optional day followed by an optional clock time, e.g.\n" +
+			"\"tomorrow 14:00\", \"fri 2pm\", \"2025-03-14 9:30\", \"16:45\" or \"in 2h\".\n" +
+			"Dates are \"today\", \"tomorrow\", a weekday, \"next <weekday>\" or YYYY-MM-DD.\n" +
+			"Durations look like \"45m\", \"1h30m\", \"1.5h\" or \"2d\".",
+		Flags: flag.NewFlagSet("add", flag.ContinueOnError),
+	}
+	at := cmd.Flags.String("at", "", "start `time` of the event")
+	date := cmd.Flags.String("date", "", "`date` of an all-day event")
+	duration := cmd.Flags.String("for", "", "`duration` of the event (default 30m, or 1d for all-day events)")
+	location := cmd.Flags.String("location", "", "location of the event")
+	description := cmd.Flags.String("description", "", "description of the event")
+
+	cmd.Run = func(env *Env, args []string) error {
+		summary := strings.TrimSpace(strings.Join(args, " "))
+		if summary == "" {
+			return &usageError{cmd: cmd, msg: "summary is required"}
+		}
+
+		in, err := newEventInput(time.Now(), *at, *date, *duration)
+		if err != nil {
+			return &usageError{cmd: cmd, msg: err.Error()}
+		}
+		in.Summary = summary
+		in.Location = *location
+		in.Description = *description
+
+		store, calendarId, err := env.writeStore()
+		if err != nil {
+			return err
+		}
+
+		evt, err := addEvent(store, calendarId, in, time.Now())
+		if err != nil {
+			return err
+		}
+
+		return env.render(evt, func(w io.Writer) error {
+			fmt.Fprintf(w, "Created event %v\n%v\n", evt.Id, evt.Link)
+			return nil
+		})
+	}
+
+	return cmd
+}
+
+// addEvent function creates the event and returns it in its output form
+func addEvent(store gcal.EventStore, calendarId string, in gcal.EventInput, now time.Time) (Event, error) {
+	c := newCalendar(store, calendarId, now)
+
+	item, err := c.AddEvent(in)
+	if err != nil {
+		return Event{}, fmt.Errorf("unable to create event: %w", err)
+	}
+
+	return newEvent(item, now)
+}
+
+// newEventInput function resolves the time flags of the add command
+func newEventInput(now time.Time, at, date, duration string) (gcal.EventInput, error) {
+	if (at == "") == (date == "") {
+		return gcal.EventInput{}, fmt.Errorf("exactly one of --at and --date is required")
+	}
+
+	if date != "" {
+		day, err := util.ParseHumanDate(date, now)
+		if err != nil {
+			return gcal.EventInput{}, err
+		}
+
+		days := 1
+		if duration != "" {
+			d, err := util.ParseHumanDuration(duration)
+			if err != nil {
+				return gcal.EventInput{}, err
+			}
+			if d <= 0 || d%(24*time.Hour) != 0 {
+				return gcal.EventInput{}, fmt.Errorf("duration of an all-day event must be whole days, e.g. 2d")
+			}
+			days = int(d / (24 * time.Hour))
+		}
+
+		return gcal.EventInput{
+			Start:  day,
+			End:    day.AddDate(0, 0, days),
+			AllDay: true,
+		}, nil
+	}
+
+	start, err := util.ParseHumanTime(at, now)
+	if err != nil {
+		return gcal.EventInput{}, err
+	}
+
+	if duration == "" {
+		duration = defaultEventDuration
+	}
+	d, err := util.ParseHumanDuration(duration)
+	if err != nil {
+		return gcal.EventInput{}, err
+	}
+	if d <= 0 {
+		return gcal.EventInput{}, fmt.Errorf("duration must be positive")
+	}
+
+	return gcal.EventInput{
+		Start: start,
+		End:   start.Add(d),
+	}, nil
+}
@@ -0,0 +1,187 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/jiyeol-lee/gcli/pkg/config"
+	"github.com/jiyeol-lee/gcli/pkg/util"
+)
+
+// defaultAgendaDays is how many days agenda shows without a range
+var defaultAgendaDays = 7
+
+// AgendaDay is a day of the agenda printed by the agenda command.
+//
+//	date     YYYY-MM-DD
+//	weekday  Mon to Sun
+//	events   events of the day, all-day and multi-day ones first, see AgendaEvent
+type AgendaDay struct {
+	Date    string        `json:"date"`
+	Weekday string        `json:"weekday"`
+	Events  []AgendaEvent `json:"events"`
+}
+
+// AgendaEvent is an Event of an AgendaDay, along with the fields of Event.
+//
+//	day   position of the day among the days the event spans, from 1
+//	days  days the event spans, 1 for events within a day
+type AgendaEvent struct {
+	Event
+	Day  int `json:"day"`
+	Days int `json:"days"`
+}
+
+// spanned method returns the first and last local days the event spans
+func (e Event) spanned() (time.Time, time.Time) {
+	first := util.StartOfDay(e.start.Local())
+
+	// The end is exclusive, an event ending at midnight is over the day before
+	last := util.StartOfDay(e.end.Local().Add(-time.Nanosecond))
+	if last.Before(first) {
+		last = first
+	}
+
+	return first, last
+}
+
+// agendaDays function returns the [from, to) days with their events, the
+// events spanning several days being listed on each of them
+func agendaDays(
+	stores storeResolver,
+	calendars []config.CalendarConfig,
+	from, to, now time.Time,
+	includeWork bool,
+) ([]AgendaDay, error) {
+	events, err := listEvents(stores, calendars, from, to, now, !includeWork)
+	if err != nil {
+		return nil, err
+	}
+
+	agenda := []AgendaDay{}
+	for d := util.StartOfDay(from); d.Before(to); d = d.AddDate(0, 0, 1) {
+		day := AgendaDay{
+			Date:    d.Format(dateLayout),
+			Weekday: d.Format("Mon"),
+			Events:  []AgendaEvent{},
+		}
+
+		for _, evt := range events {
+			first, last := evt.spanned()
+			if d.Before(first) || d.After(last) {
+				continue
+			}
+
+			day.Events = append(day.Events, AgendaEvent{
+				Event: evt,
+				Day:   daysBetween(first, d) + 1,
+				Days:  daysBetween(first, last) + 1,
+			})
+		}
+
+		// All-day and multi-day events come first, in the order of their start
+		slices.SortStableFunc(day.Events, func(a, b AgendaEvent) int {
+			aTop, bTop := a.AllDay || a.Days > 1, b.AllDay || b.Days > 1
+			switch {
+			case aTop && !bTop:
+				return -1
+			case !aTop && bTop:
+				return 1
+			}
+			return 0
+		})
+
+		agenda = append(agenda, day)
+	}
+
+	return agenda, nil
+}
+
+// daysBetween function returns the calendar days from a to b, both local midnights
+func daysBetween(a, b time.Time) int {
+	days := 0
+	for d := a; d.Before(b); d = d.AddDate(0, 0, 1) {
+		days++
+	}
+
+	return days
+}
+
+// when method returns the time of the event on its day of the agenda, e.g.
+// "09:30-10:00", "all day", "from 22:00" or "until 02:00"
+func (e AgendaEvent) when() string {
+	switch {
+	case e.AllDay:
+		return "all day"
+	case e.Days == 1:
+		return fmt.Sprintf("%v-%v", e.start.Local().Format("15:04"), e.end.Local().Format("15:04"))
+	case e.Day == 1:
+		return "from " + e.start.Local().Format("15:04")
+	case e.Day == e.Days:
+		return "until " + e.end.Local().Format("15:04")
+	}
+
+	return "all day"
+}
+
+func newAgendaCommand() *Command {
+	cmd := &Command{
+		Name:  "agenda",
+		Short: "Show the events of the coming days, all-day events included",
+		Long: "Show the events of the coming days under a heading per day. All-day events and the\n" +
+			"events spanning several days come first, the latter being shown on each of their\n" +
+			"days with the position of the day, e.g. \"day 2/3\".",
+		Flags: flag.NewFlagSet("agenda", flag.ContinueOnError),
+	}
+	dates := addRangeFlags(cmd.Flags)
+	includeWork := cmd.Flags.Bool("work", false, "include the work sessions and totals of the work tracker")
+
+	cmd.Run = func(env *Env, args []string) error {
+		if err := noArgs(cmd, args); err != nil {
+			return err
+		}
+
+		if dates.from == "" && dates.to == "" && !dates.tomorrow && !dates.week && dates.days == 0 {
+			dates.days = defaultAgendaDays
+		}
+		now := time.Now()
+		from, to, err := dates.resolve(now)
+		if err != nil {
+			return &usageError{cmd: cmd, msg: err.Error()}
+		}
+
+		calendars := env.Calendars()
+		agenda, err := agendaDays(env.Store, calendars, from, to, now, *includeWork)
+		if err != nil {
+			return err
+		}
+
+		return env.render(agenda, func(w io.Writer) error {
+			for i, day := range agenda {
+				if i > 0 {
+					fmt.Fprintln(w)
+				}
+				fmt.Fprintf(w, "%v %v\n", day.Weekday, day.Date)
+
+				if len(day.Events) == 0 {
+					fmt.Fprintln(w, "  No events")
+					continue
+				}
+				for _, evt := range day.Events {
+					span := ""
+					if evt.Days > 1 {
+						span = fmt.Sprintf(" (day %v/%v)", evt.Day, evt.Days)
+					}
+					fmt.Fprintf(w, "  %-12v %v%v%v\n", evt.when(), calendarPrefix(calendars, evt.Event), evt.Summary, span)
+				}
+			}
+
+			return nil
+		})
+	}
+
+	return cmd
+}
@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+func TestAgendaDays(t *testing.T) {
+	day := time.Date(2025, 3, 12, 0, 0, 0, 0, time.Local)
+	store := newTestStore(t, day)
+	at := func(d, h, m int) string {
+		return day.AddDate(0, 0, d).Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute).Format(time.RFC3339)
+	}
+	for _, e := range []*calendar.Event{
+		{Summary: "Conference", Start: &calendar.EventDateTime{Date: "2025-03-12"}, End: &calendar.EventDateTime{Date: "2025-03-15"}},
+		{Summary: "Release", Start: &calendar.EventDateTime{DateTime: at(0, 22, 0)}, End: &calendar.EventDateTime{DateTime: at(1, 2, 0)}},
+		{Summary: "Retro", Start: &calendar.EventDateTime{DateTime: at(1, 15, 0)}, End: &calendar.EventDateTime{DateTime: at(1, 16, 0)}},
+	} {
+		if _, err := store.Insert("primary", e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	agenda, err := agendaDays(singleStore(store), primary, day, day.AddDate(0, 0, 4), day, false)
+	if err != nil {
+		t.Fatalf("agendaDays() error = %v", err)
+	}
+
+	var got []string
+	for _, d := range agenda {
+		got = append(got, d.Weekday+" "+d.Date)
+		for _, evt := range d.Events {
+			got = append(got, fmt.Sprintf("%v %v %v/%v", evt.when(), evt.Summary, evt.Day, evt.Days))
+		}
+	}
+	want := []string{
+		"Wed 2025-03-12",
+		"all day Holiday 1/1",
+		"all day Conference 1/3",
+		"from 22:00 Release 1/2",
+		"09:30-09:45 Standup 1/1",
+		"12:00-13:00 Lunch 1/1",
+		"Thu 2025-03-13",
+		"all day Conference 2/3",
+		"until 02:00 Release 2/2",
+		"15:00-16:00 Retro 1/1",
+		"Fri 2025-03-14",
+		"all day Conference 3/3",
+		"Sat 2025-03-15",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("agendaDays() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,184 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jiyeol-lee/gcli/pkg/config"
+	"github.com/jiyeol-lee/gcli/pkg/gcal"
+	"github.com/jiyeol-lee/gcli/pkg/goauth"
+)
+
+// ProfileInfo is the machine-readable form of an auth profile printed by the
+// auth list and auth login commands.
+//
+//	name       profile name, to be given to --profile
+//	has_token  true when the profile is signed in
+//	default    true for the profile used when no --profile is given
+type ProfileInfo struct {
+	Name     string `json:"name"`
+	HasToken bool   `json:"has_token"`
+	Default  bool   `json:"default"`
+}
+
+// profileNames function returns the known auth profiles: the configured ones,
+// the ones having a token file and the default one, sorted by name
+func profileNames(cfg *config.Config, tokenDir string) []string {
+	names := []string{cfg.GetDefaultProfile()}
+	if cfg != nil {
+		for name := range cfg.Profiles {
+			names = append(names, name)
+		}
+		for _, cal := range cfg.Calendars {
+			if cal.Profile != "" {
+				names = append(names, cal.Profile)
+			}
+		}
+	}
+
+	entries, _ := os.ReadDir(tokenDir)
+	for _, entry := range entries {
+		if name, ok := strings.CutSuffix(entry.Name(), ".json"); ok && !entry.IsDir() {
+			names = append(names, name)
+		}
+	}
+
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
+// listProfiles method returns the known auth profiles and whether they are signed in
+func (e *Env) listProfiles() ([]ProfileInfo, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := []ProfileInfo{}
+	for _, name := range profileNames(e.Config, filepath.Join(dir, "tokens")) {
+		o, err := e.oauth(name)
+		if err != nil {
+			return nil, err
+		}
+
+		profiles = append(profiles, ProfileInfo{
+			Name:     name,
+			HasToken: o.HasToken(),
+			Default:  name == e.Config.GetDefaultProfile(),
+		})
+	}
+
+	return profiles, nil
+}
+
+func newAuthCommand() *Command {
+	return &Command{
+		Name:  "auth",
+		Args:  "<command>",
+		Short: "Manage the auth profiles",
+		Long: "Manage the auth profiles. Each profile is signed into its own Google account,\n" +
+			"with its own token and, optionally, its own OAuth client in the config file.",
+		Flags: flag.NewFlagSet("auth", flag.ContinueOnError),
+		Subcommands: []*Command{
+			newAuthListCommand(),
+			newAuthLoginCommand(),
+		},
+	}
+}
+
+func newAuthListCommand() *Command {
+	cmd := &Command{
+		Name:  "list",
+		Short: "List the auth profiles",
+		Flags: flag.NewFlagSet("list", flag.ContinueOnError),
+	}
+
+	cmd.Run = func(env *Env, args []string) error {
+		if err := noArgs(cmd, args); err != nil {
+			return err
+		}
+
+		profiles, err := env.listProfiles()
+		if err != nil {
+			return err
+		}
+
+		return env.render(profiles, func(w io.Writer) error {
+			tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+			for _, p := range profiles {
+				mark := " "
+				if p.Default {
+					mark = "*"
+				}
+				status := "signed out"
+				if p.HasToken {
+					status = "signed in"
+				}
+				fmt.Fprintf(tw, "%v %v\t%v\n", mark, p.Name, status)
+			}
+
+			return tw.Flush()
+		})
+	}
+
+	return cmd
+}
+
+func newAuthLoginCommand() *Command {
+	var mode string
+	cmd := &Command{
+		Name:  "login",
+		Short: "Sign the auth profiles given to --profile in",
+		Long: "Sign the auth profiles given to --profile in. With --mode browser, the consent\n" +
+			"page opens in $BROWSER or the browser of the system. With --mode manual, the URL\n" +
+			"is printed and the code, or the URL the browser was redirected to, is read from\n" +
+			"the input. With --mode device, a code is entered on a page opened on any device;\n" +
+			"Google does not allow the Calendar scopes in this flow, so it only works with the\n" +
+			"device_auth_url of another authorization server configured for the profile. By\n" +
+			"default, manual is used over SSH or without a display, browser otherwise.",
+		Flags: flag.NewFlagSet("login", flag.ContinueOnError),
+	}
+	cmd.Flags.StringVar(&mode, "mode", "", "how to sign in: browser, manual or device")
+
+	cmd.Run = func(env *Env, args []string) error {
+		if err := noArgs(cmd, args); err != nil {
+			return err
+		}
+		if _, err := goauth.ParseLoginMode(mode); err != nil {
+			return &usageError{cmd: cmd, msg: err.Error()}
+		}
+		env.LoginMode = mode
+
+		profiles := []ProfileInfo{}
+		for _, profile := range env.profiles() {
+			o, err := env.oauth(profile)
+			if err != nil {
+				return err
+			}
+			if _, err := gcal.NewGoogleStore(o); err != nil {
+				return fmt.Errorf("profile %v: %w", profile, err)
+			}
+
+			profiles = append(profiles, ProfileInfo{
+				Name:     profile,
+				HasToken: o.HasToken(),
+				Default:  profile == env.Config.GetDefaultProfile(),
+			})
+		}
+
+		return env.render(profiles, func(w io.Writer) error {
+			for _, p := range profiles {
+				fmt.Fprintf(w, "Profile %v signed in\n", p.Name)
+			}
+			return nil
+		})
+	}
+
+	return cmd
+}
@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/jiyeol-lee/gcli/pkg/config"
+)
+
+func TestProfileNames(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"work.json", "personal.json", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		cfg      *config.Config
+		tokenDir string
+		want     []string
+	}{
+		{
+			name:     "When nothing is configured, return the default profile",
+			cfg:      &config.Config{},
+			tokenDir: filepath.Join(dir, "missing"),
+			want:     []string{"default"},
+		},
+		{
+			name: "Configured profiles and token files are merged",
+			cfg: &config.Config{
+				DefaultProfile: "work",
+				Profiles:       map[string]config.ProfileConfig{"oss": {}},
+				Calendars:      []config.CalendarConfig{{Id: "primary", Profile: "personal"}},
+			},
+			tokenDir: dir,
+			want:     []string{"oss", "personal", "work"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := profileNames(tt.cfg, tt.tokenDir); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("profileNames() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Status bars accepted by --bar
+const (
+	barWaybar   = "waybar"
+	barI3blocks = "i3blocks"
+	barPolybar  = "polybar"
+	barTmux     = "tmux"
+)
+
+var barFormats = []string{barWaybar, barI3blocks, barPolybar, barTmux}
+
+// Urgency classes of the status-bar output, from how soon the next event starts
+const (
+	urgencyImminent = "imminent"
+	urgencySoon     = "soon"
+	urgencyBusy     = "busy"
+	urgencyFree     = "free"
+)
+
+var (
+	// imminentWithin is how soon the next event starts for the imminent class
+	imminentWithin = 5 * time.Minute
+	// soonWithin is how soon the next event starts for the soon class
+	soonWithin = 15 * time.Minute
+)
+
+// urgencyColors are the colors of the urgency classes, the free class keeping
+// the color of the bar
+var urgencyColors = map[string]string{
+	urgencyImminent: "#ff5555",
+	urgencySoon:     "#ffb86c",
+	urgencyBusy:     "#8be9fd",
+}
+
+// barStatus is what the status-bar commands print with --bar
+type barStatus struct {
+	text    string
+	short   string
+	tooltip string
+	class   string
+}
+
+// newBarStatus function returns the status of the bar showing text, whose
+// class is given by the next event of the day and whose tooltip lists the
+// events of the day not over yet
+func newBarStatus(text, short string, events []Event, now time.Time) barStatus {
+	return barStatus{
+		text:    text,
+		short:   short,
+		tooltip: agendaTooltip(events, now),
+		class:   urgency(events, now),
+	}
+}
+
+// urgency function returns the urgency class of the day: imminent or soon when
+// the next timed event starts within imminentWithin or soonWithin, busy while
+// an event is in progress, free otherwise
+func urgency(events []Event, now time.Time) string {
+	busy := false
+	for _, evt := range events {
+		if evt.AllDay {
+			continue
+		}
+
+		if evt.start.After(now) {
+			switch until := evt.start.Sub(now); {
+			case until <= imminentWithin:
+				return urgencyImminent
+			case until <= soonWithin:
+				return urgencySoon
+			}
+			continue
+		}
+		if evt.end.After(now) {
+			busy = true
+		}
+	}
+
+	if busy {
+		return urgencyBusy
+	}
+
+	return urgencyFree
+}
+
+// agendaTooltip function returns the events of the day not over yet, a line each
+func agendaTooltip(events []Event, now time.Time) string {
+	var lines []string
+	for _, evt := range events {
+		if !evt.end.After(now) {
+			continue
+		}
+
+		if evt.AllDay {
+			lines = append(lines, "All day "+evt.Summary)
+			continue
+		}
+		lines = append(lines, fmt.Sprintf(
+			"%v-%v %v",
+			evt.start.Local().Format("15:04"),
+			evt.end.Local().Format("15:04"),
+			evt.Summary,
+		))
+	}
+
+	if len(lines) == 0 {
+		return "Nothing else today"
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// write method writes the status in the protocol of the bar
+func (s barStatus) write(w io.Writer, bar string) error {
+	color := urgencyColors[s.class]
+
+	switch bar {
+	case barWaybar:
+		// waybar reads the text and tooltip as Pango markup
+		markup := strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+		enc := json.NewEncoder(w)
+		enc.SetEscapeHTML(false)
+		return enc.Encode(struct {
+			Text    string `json:"text"`
+			Tooltip string `json:"tooltip"`
+			Class   string `json:"class"`
+		}{
+			Text:    markup.Replace(s.text),
+			Tooltip: markup.Replace(s.tooltip),
+			Class:   s.class,
+		})
+
+	case barI3blocks:
+		// full_text, short_text and color, a line each
+		_, err := fmt.Fprintf(w, "%v\n%v\n%v\n", s.text, s.short, color)
+		return err
+
+	case barPolybar:
+		text := strings.ReplaceAll(s.text, "%", "%%")
+		if color != "" {
+			text = fmt.Sprintf("%%{F%v}%v%%{F-}", color, text)
+		}
+		_, err := fmt.Fprintln(w, text)
+		return err
+
+	case barTmux:
+		text := strings.ReplaceAll(s.text, "#", "##")
+		if color != "" {
+			text = fmt.Sprintf("#[fg=%v]%v#[default]", color, text)
+		}
+		_, err := fmt.Fprintln(w, text)
+		return err
+	}
+
+	return fmt.Errorf("unknown status bar %q", bar)
+}
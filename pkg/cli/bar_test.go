@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestUrgency(t *testing.T) {
+	day := time.Date(2025, 3, 12, 0, 0, 0, 0, time.Local)
+	events, err := todayEvents(singleStore(newTestStore(t, day)), primary, day)
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := func(h, m int) time.Time {
+		return day.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute)
+	}
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want string
+	}{
+		{name: "Next event within 5 minutes", now: at(9, 26), want: urgencyImminent},
+		{name: "Next event within 15 minutes", now: at(9, 20), want: urgencySoon},
+		{name: "Event in progress, next one later", now: at(9, 35), want: urgencyBusy},
+		{name: "Event in progress, nothing after it", now: at(12, 58), want: urgencyBusy},
+		{name: "Nothing soon, all-day events are left out", now: at(8, 0), want: urgencyFree},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := urgency(events, tt.now); got != tt.want {
+				t.Errorf("urgency() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAgendaTooltip(t *testing.T) {
+	day := time.Date(2025, 3, 12, 0, 0, 0, 0, time.Local)
+	events, err := todayEvents(singleStore(newTestStore(t, day)), primary, day)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "All day Holiday\n12:00-13:00 Lunch"
+	if got := agendaTooltip(events, day.Add(10*time.Hour)); got != want {
+		t.Errorf("agendaTooltip() = %q, want %q", got, want)
+	}
+
+	want = "Nothing else today"
+	if got := agendaTooltip(events, day.AddDate(0, 0, 1)); got != want {
+		t.Errorf("agendaTooltip() = %q, want %q", got, want)
+	}
+}
+
+func TestBarStatusWrite(t *testing.T) {
+	status := barStatus{
+		text:    "[Q&A #3] in 4min",
+		short:   "in 4min",
+		tooltip: "09:30-10:00 Q&A #3",
+		class:   urgencyImminent,
+	}
+	tests := []struct {
+		bar  string
+		want string
+	}{
+		{
+			bar:  barWaybar,
+			want: `{"text":"[Q&amp;A #3] in 4min","tooltip":"09:30-10:00 Q&amp;A #3","class":"imminent"}` + "\n",
+		},
+		{
+			bar:  barI3blocks,
+			want: "[Q&A #3] in 4min\nin 4min\n#ff5555\n",
+		},
+		{
+			bar:  barPolybar,
+			want: "%{F#ff5555}[Q&A #3] in 4min%{F-}\n",
+		},
+		{
+			bar:  barTmux,
+			want: "#[fg=#ff5555][Q&A ##3] in 4min#[default]\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.bar, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := status.write(&buf, tt.bar); err != nil {
+				t.Fatalf("write() error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("write() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jiyeol-lee/gcli/pkg/gcal"
+)
+
+// stringsFlag is a flag.Value collecting the values of a repeatable flag.
+// Values may also be comma-separated.
+type stringsFlag []string
+
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringsFlag) Set(value string) error {
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			*f = append(*f, v)
+		}
+	}
+
+	return nil
+}
+
+// CalendarInfo is the machine-readable form of a calendar printed by the
+// calendars command.
+//
+//	id           calendar id, to be given to --calendar
+//	summary      calendar title
+//	primary      true for the primary calendar of the account
+//	access_role  owner, writer, reader or freeBusyReader
+//	selected     true when the calendar is used by the other commands
+//	profile      auth profile of the account
+type CalendarInfo struct {
+	Id         string `json:"id"`
+	Summary    string `json:"summary"`
+	Primary    bool   `json:"primary"`
+	AccessRole string `json:"access_role"`
+	Selected   bool   `json:"selected"`
+	Profile    string `json:"profile"`
+}
+
+// listCalendars function returns the calendars of the account of the auth
+// profile, marking the ones among selected
+func listCalendars(store gcal.EventStore, profile string, selected []string) ([]CalendarInfo, error) {
+	lister, ok := store.(gcal.CalendarLister)
+	if !ok {
+		return nil, fmt.Errorf("the store cannot list calendars")
+	}
+
+	entries, err := lister.ListCalendars()
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve calendars of profile %v: %w", profile, err)
+	}
+
+	calendars := []CalendarInfo{}
+	for _, entry := range entries {
+		calendars = append(calendars, CalendarInfo{
+			Id:         entry.Id,
+			Summary:    entry.Summary,
+			Primary:    entry.Primary,
+			AccessRole: entry.AccessRole,
+			Selected: slices.Contains(selected, entry.Id) ||
+				(entry.Primary && slices.Contains(selected, "primary")),
+			Profile: profile,
+		})
+	}
+
+	return calendars, nil
+}
+
+func newCalendarsCommand() *Command {
+	cmd := &Command{
+		Name:  "calendars",
+		Short: "List the calendars of the accounts",
+		Long: "List the calendars of the accounts of the auth profiles. Calendars marked with *\n" +
+			"are the ones the other commands use, see --calendar and --profile.",
+		Flags: flag.NewFlagSet("calendars", flag.ContinueOnError),
+	}
+
+	cmd.Run = func(env *Env, args []string) error {
+		if err := noArgs(cmd, args); err != nil {
+			return err
+		}
+
+		profiles := env.profiles()
+		calendars := []CalendarInfo{}
+		for _, profile := range profiles {
+			store, err := env.Store(profile)
+			if err != nil {
+				return err
+			}
+
+			var selected []string
+			for _, cal := range env.Calendars() {
+				if cal.Profile == profile {
+					selected = append(selected, cal.Id)
+				}
+			}
+
+			found, err := listCalendars(store, profile, selected)
+			if err != nil {
+				return err
+			}
+			calendars = append(calendars, found...)
+		}
+
+		return env.render(calendars, func(w io.Writer) error {
+			tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+			for _, cal := range calendars {
+				mark := " "
+				if cal.Selected {
+					mark = "*"
+				}
+				if len(profiles) > 1 {
+					fmt.Fprintf(tw, "%v %v\t%v\t%v\t%v\n", mark, cal.Profile, cal.Id, cal.Summary, cal.AccessRole)
+					continue
+				}
+				fmt.Fprintf(tw, "%v %v\t%v\t%v\n", mark, cal.Id, cal.Summary, cal.AccessRole)
+			}
+
+			return tw.Flush()
+		})
+	}
+
+	return cmd
+}
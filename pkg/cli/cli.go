@@ -0,0 +1,513 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jiyeol-lee/gcli/pkg/config"
+	"github.com/jiyeol-lee/gcli/pkg/gcal"
+	"github.com/jiyeol-lee/gcli/pkg/goauth"
+)
+
+// Exit codes returned by Run
+const (
+	ExitOK    = 0
+	ExitError = 1
+	ExitUsage = 2
+)
+
+// Command describes a command of the command tree. A command either runs
+// itself or dispatches to one of its subcommands.
+type Command struct {
+	Name        string
+	Args        string
+	Short       string
+	Long        string
+	Flags       *flag.FlagSet
+	Subcommands []*Command
+	Run         func(env *Env, args []string) error
+
+	parent *Command
+}
+
+// Env holds everything a command needs to run
+type Env struct {
+	Stdin   io.Reader
+	Stdout  io.Writer
+	Stderr  io.Writer
+	Version string
+	Output  string
+	Config  *config.Config
+	// Offline serves events only from the cache
+	Offline bool
+	// CacheTTL is how long cached events are served without calling the API
+	CacheTTL time.Duration
+	// CalendarNames are the ids or labels given to --calendar
+	CalendarNames stringsFlag
+	// Profiles are the auth profiles given to --profile
+	Profiles stringsFlag
+	// LoginMode is how to sign in when no token is stored, overriding the profile configuration
+	LoginMode string
+
+	stores map[string]gcal.EventStore
+}
+
+// usageError is returned when the command line is invalid
+type usageError struct {
+	cmd *Command
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+// Store method returns the event store of the auth profile, creating it on
+// first use so that only the commands talking to the API go through OAuth.
+// Listed events are cached on disk; offline, the API is not used at all.
+func (e *Env) Store(profile string) (gcal.EventStore, error) {
+	if store, ok := e.stores[profile]; ok {
+		return store, nil
+	}
+	// Unlike the ones given to --profile, configured profiles are not checked yet
+	if err := config.ValidateProfileName(profile); err != nil {
+		return nil, err
+	}
+
+	dir, err := config.CacheDir()
+	if err != nil {
+		return nil, err
+	}
+
+	cached := &gcal.CachedStore{
+		Dir:      filepath.Join(dir, "events", profile),
+		TTL:      e.CacheTTL,
+		Offline:  e.Offline,
+		Warnings: e.Stderr,
+	}
+	if !e.Offline {
+		o, err := e.oauth(profile)
+		if err != nil {
+			return nil, err
+		}
+		store, err := gcal.NewGoogleStore(o)
+		if err != nil {
+			return nil, fmt.Errorf("profile %v: %w", profile, err)
+		}
+		cached.Store = store
+	}
+
+	if e.stores == nil {
+		e.stores = map[string]gcal.EventStore{}
+	}
+	e.stores[profile] = cached
+
+	return cached, nil
+}
+
+// oauth method returns the OAuth client of the auth profile. The default
+// profile keeps the default token file of goauth, which migrates the token of
+// previous versions.
+func (e *Env) oauth(profile string) (*goauth.OAuth, error) {
+	tokenFile := ""
+	if profile != config.DefaultProfileName {
+		path, err := config.TokenPath(profile)
+		if err != nil {
+			return nil, err
+		}
+		tokenFile = path
+	}
+
+	p := e.Config.GetProfile(profile)
+
+	credentialsFile := p.CredentialsFile
+	if credentialsFile != "" && !filepath.IsAbs(credentialsFile) {
+		dir, err := config.Dir()
+		if err != nil {
+			return nil, err
+		}
+		credentialsFile = filepath.Join(dir, credentialsFile)
+	}
+
+	mode := p.Login
+	if e.LoginMode != "" {
+		mode = e.LoginMode
+	}
+	login, err := goauth.ParseLoginMode(mode)
+	if err != nil {
+		return nil, fmt.Errorf("profile %v: %w", profile, err)
+	}
+
+	return &goauth.OAuth{
+		TokenFile:       tokenFile,
+		ClientID:        p.ClientID,
+		ClientSecret:    p.ClientSecret,
+		CredentialsFile: credentialsFile,
+		Login:           login,
+		CallbackPort:    p.CallbackPort,
+		TokenURL:        p.TokenURL,
+		DeviceAuthURL:   p.DeviceAuthURL,
+		In:              e.Stdin,
+		Out:             e.Stderr,
+	}, nil
+}
+
+// profiles method returns the auth profiles given to --profile, or the default one
+func (e *Env) profiles() []string {
+	if len(e.Profiles) == 0 {
+		return []string{e.Config.GetDefaultProfile()}
+	}
+
+	return e.Profiles
+}
+
+// Calendars method returns the calendars the commands read from
+func (e *Env) Calendars() []config.CalendarConfig {
+	return e.Config.ResolveCalendars(e.CalendarNames, e.Profiles)
+}
+
+// WriteCalendar method returns the calendar the commands write to, the first
+// of the selected calendars
+func (e *Env) WriteCalendar() config.CalendarConfig {
+	return e.Calendars()[0]
+}
+
+// writeStore method returns the store and id of the calendar the commands write to
+func (e *Env) writeStore() (gcal.EventStore, string, error) {
+	cal := e.WriteCalendar()
+
+	store, err := e.Store(cal.Profile)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return store, cal.Id, nil
+}
+
+// storeResolver returns the event store of an auth profile
+type storeResolver func(profile string) (gcal.EventStore, error)
+
+// singleStore function returns a storeResolver serving the store for every profile
+func singleStore(store gcal.EventStore) storeResolver {
+	return func(_ string) (gcal.EventStore, error) {
+		return store, nil
+	}
+}
+
+// newCalendar function returns the calendar backed by the store, frozen at now
+func newCalendar(store gcal.EventStore, calendarId string, now time.Time) *gcal.Calendar {
+	return &gcal.Calendar{
+		Id:    calendarId,
+		Store: store,
+		Now: func() time.Time {
+			return now
+		},
+	}
+}
+
+// Run function runs the command line and returns the process exit code
+func Run(args []string, version string) int {
+	env := &Env{
+		Stdin:   os.Stdin,
+		Stdout:  os.Stdout,
+		Stderr:  os.Stderr,
+		Version: version,
+		Output:  OutputText,
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(env.Stderr, "gcli: unable to load configuration: %v\n", err)
+		return ExitError
+	}
+	env.Config = cfg
+
+	return run(env, newRootCommand(), args)
+}
+
+func run(env *Env, root *Command, args []string) int {
+	if env.Output == "" {
+		env.Output = OutputText
+	}
+	ttl, err := env.Config.GetCacheTTL()
+	if err != nil {
+		fmt.Fprintf(env.Stderr, "gcli: unable to load configuration: %v\n", err)
+		return ExitError
+	}
+	env.CacheTTL = ttl
+	root.link(nil)
+	root.walk(func(c *Command) {
+		addGlobalFlags(c.Flags, env)
+	})
+
+	err = root.execute(env, args)
+	if err == nil {
+		return ExitOK
+	}
+
+	var uerr *usageError
+	if errors.As(err, &uerr) {
+		fmt.Fprintf(env.Stderr, "gcli: %v\n", uerr.msg)
+		fmt.Fprintf(env.Stderr, "Run '%v' for usage.\n", uerr.cmd.helpHint())
+		return ExitUsage
+	}
+
+	fmt.Fprintf(env.Stderr, "gcli: %v\n", err)
+	return ExitError
+}
+
+func newRootCommand() *Command {
+	var showVersion bool
+
+	root := &Command{
+		Name:  "gcli",
+		Args:  "<command> [flags]",
+		Short: "Google Calendar from the command line",
+		Long: "Google Calendar from the command line.\n\n" +
+			"Every command accepts --output json or --output ndjson to print structured\n" +
+			"objects instead of text; see 'go doc github.com/jiyeol-lee/gcli/pkg/cli Event'\n" +
+			"for the event schema.",
+		Flags: flag.NewFlagSet("gcli", flag.ContinueOnError),
+	}
+	root.Flags.BoolVar(&showVersion, "version", false, "print the version and exit")
+
+	root.Subcommands = []*Command{
+		newListCommand(),
+		newSoonCommand(),
+		newInProgressCommand(),
+		newNextCommand(),
+		newJoinCommand(),
+		newAgendaCommand(),
+		newFreeCommand(),
+		newScheduleCommand(),
+		newAddCommand(),
+		newCalendarsCommand(),
+		newAuthCommand(),
+		newWorkCommand(),
+		newHelpCommand(root),
+		newVersionCommand(),
+	}
+
+	root.Run = func(env *Env, args []string) error {
+		if showVersion {
+			return root.find("version").Run(env, nil)
+		}
+
+		return root.dispatch(env, args)
+	}
+
+	return root
+}
+
+func newHelpCommand(root *Command) *Command {
+	return &Command{
+		Name:  "help",
+		Args:  "[command]...",
+		Short: "Show help for a command",
+		Flags: flag.NewFlagSet("help", flag.ContinueOnError),
+		Run: func(env *Env, args []string) error {
+			cmd := root
+			for _, name := range args {
+				sub := cmd.find(name)
+				if sub == nil {
+					return &usageError{cmd: root, msg: fmt.Sprintf("unknown help topic %q", strings.Join(args, " "))}
+				}
+				cmd = sub
+			}
+			cmd.usage(env.Stdout)
+
+			return nil
+		},
+	}
+}
+
+func newVersionCommand() *Command {
+	return &Command{
+		Name:  "version",
+		Short: "Print the version",
+		Flags: flag.NewFlagSet("version", flag.ContinueOnError),
+		Run: func(env *Env, args []string) error {
+			return env.render(map[string]string{"version": env.Version}, func(w io.Writer) error {
+				fmt.Fprintf(w, "gcli %v\n", env.Version)
+				return nil
+			})
+		},
+	}
+}
+
+// link method sets the parent of every command in the tree
+func (c *Command) link(parent *Command) {
+	c.parent = parent
+	if c.Flags == nil {
+		c.Flags = flag.NewFlagSet(c.Name, flag.ContinueOnError)
+	}
+	for _, sub := range c.Subcommands {
+		sub.link(c)
+	}
+}
+
+// walk method calls fn for the command and all of its descendants
+func (c *Command) walk(fn func(c *Command)) {
+	fn(c)
+	for _, sub := range c.Subcommands {
+		sub.walk(fn)
+	}
+}
+
+// path method returns the full command name, e.g. "gcli work start"
+func (c *Command) path() string {
+	if c.parent == nil {
+		return c.Name
+	}
+
+	return c.parent.path() + " " + c.Name
+}
+
+func (c *Command) helpHint() string {
+	if c.parent == nil {
+		return "gcli help"
+	}
+
+	return "gcli help " + strings.TrimPrefix(c.path(), "gcli ")
+}
+
+func (c *Command) find(name string) *Command {
+	for _, sub := range c.Subcommands {
+		if sub.Name == name {
+			return sub
+		}
+	}
+
+	return nil
+}
+
+// execute method parses the flags of the command and runs it
+func (c *Command) execute(env *Env, args []string) error {
+	c.Flags.SetOutput(io.Discard)
+
+	var rest []string
+	var err error
+	if len(c.Subcommands) > 0 {
+		err = c.Flags.Parse(args)
+		rest = c.Flags.Args()
+	} else {
+		rest, err = parseInterspersed(c.Flags, args)
+	}
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			c.usage(env.Stdout)
+			return nil
+		}
+		return &usageError{cmd: c, msg: err.Error()}
+	}
+	if err := validateOutput(c, env); err != nil {
+		return err
+	}
+	if err := validateProfiles(c, env); err != nil {
+		return err
+	}
+
+	if c.Run == nil {
+		return c.dispatch(env, rest)
+	}
+
+	return c.Run(env, rest)
+}
+
+// parseInterspersed function parses flags placed before, between or after the
+// positional arguments and returns the positional arguments. Everything after
+// "--" is positional.
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+
+		rest := fs.Args()
+		if len(rest) == 0 {
+			return positional, nil
+		}
+
+		consumed := len(args) - len(rest)
+		if consumed > 0 && args[consumed-1] == "--" {
+			return append(positional, rest...), nil
+		}
+
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+// dispatch method runs the subcommand named by the first argument
+func (c *Command) dispatch(env *Env, args []string) error {
+	if len(args) == 0 {
+		c.usage(env.Stderr)
+		return &usageError{cmd: c, msg: fmt.Sprintf("no command provided for %q", c.path())}
+	}
+
+	sub := c.find(args[0])
+	if sub == nil {
+		return &usageError{cmd: c, msg: fmt.Sprintf("unknown command %q for %q", args[0], c.path())}
+	}
+
+	return sub.execute(env, args[1:])
+}
+
+// usage method writes the help text of the command
+func (c *Command) usage(w io.Writer) {
+	fmt.Fprintf(w, "Usage: %v", c.path())
+	if c.Args != "" {
+		fmt.Fprintf(w, " %v", c.Args)
+	} else if hasFlags(c.Flags) {
+		fmt.Fprint(w, " [flags]")
+	}
+	fmt.Fprintln(w)
+
+	if c.Long != "" {
+		fmt.Fprintf(w, "\n%v\n", c.Long)
+	} else if c.Short != "" {
+		fmt.Fprintf(w, "\n%v\n", c.Short)
+	}
+
+	if len(c.Subcommands) > 0 {
+		fmt.Fprintln(w, "\nCommands:")
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		for _, sub := range c.Subcommands {
+			fmt.Fprintf(tw, "  %v\t%v\n", sub.Name, sub.Short)
+		}
+		tw.Flush()
+	}
+
+	if hasFlags(c.Flags) {
+		fmt.Fprintln(w, "\nFlags:")
+		c.Flags.SetOutput(w)
+		c.Flags.PrintDefaults()
+		c.Flags.SetOutput(io.Discard)
+	}
+}
+
+func hasFlags(fs *flag.FlagSet) bool {
+	has := false
+	fs.VisitAll(func(_ *flag.Flag) {
+		has = true
+	})
+
+	return has
+}
+
+// noArgs function returns a usage error when positional arguments are given
+func noArgs(cmd *Command, args []string) error {
+	if len(args) > 0 {
+		return &usageError{cmd: cmd, msg: fmt.Sprintf("unexpected argument %q", args[0])}
+	}
+
+	return nil
+}
@@ -0,0 +1,160 @@
+package cli
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	type args struct {
+		args []string
+	}
+	tests := []struct {
+		name       string
+		args       args
+		wantCode   int
+		wantStdout string
+		wantStderr string
+	}{
+		{
+			name:       "When no command is provided, exit with usage error",
+			args:       args{args: []string{}},
+			wantCode:   ExitUsage,
+			wantStderr: "no command provided",
+		},
+		{
+			name:       "When command is unknown, exit with usage error",
+			args:       args{args: []string{"bogus"}},
+			wantCode:   ExitUsage,
+			wantStderr: `unknown command "bogus"`,
+		},
+		{
+			name:       "When subcommand is unknown, point to the help of the parent",
+			args:       args{args: []string{"work", "bogus"}},
+			wantCode:   ExitUsage,
+			wantStderr: "Run 'gcli help work' for usage.",
+		},
+		{
+			name:       "When flag is unknown, exit with usage error",
+			args:       args{args: []string{"soon", "--bogus"}},
+			wantCode:   ExitUsage,
+			wantStderr: "flag provided but not defined: -bogus",
+		},
+		{
+			name:       "When --version is given, print the version",
+			args:       args{args: []string{"--version"}},
+			wantCode:   ExitOK,
+			wantStdout: "gcli test",
+		},
+		{
+			name:       "When help is given a command, print its usage",
+			args:       args{args: []string{"help", "work", "start"}},
+			wantCode:   ExitOK,
+			wantStdout: "Usage: gcli work start",
+		},
+		{
+			name:       "When -h is given, print the usage of the command",
+			args:       args{args: []string{"soon", "-h"}},
+			wantCode:   ExitOK,
+			wantStdout: "-max-length",
+		},
+		{
+			name:       "When --format is not a valid template, exit with usage error",
+			args:       args{args: []string{"soon", "--format", "{{.Summary"}},
+			wantCode:   ExitUsage,
+			wantStderr: "invalid --format",
+		},
+		{
+			name:       "When --profile is not a valid name, exit with usage error",
+			args:       args{args: []string{"list", "--profile", "../../x"}},
+			wantCode:   ExitUsage,
+			wantStderr: `invalid profile "../../x"`,
+		},
+		{
+			name:       "When help topic is unknown, exit with usage error",
+			args:       args{args: []string{"help", "bogus"}},
+			wantCode:   ExitUsage,
+			wantStderr: `unknown help topic "bogus"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			env := &Env{
+				Stdout:  &stdout,
+				Stderr:  &stderr,
+				Version: "test",
+			}
+			if got := run(env, newRootCommand(), tt.args.args); got != tt.wantCode {
+				t.Errorf("run() = %v, want %v (stderr: %v)", got, tt.wantCode, stderr.String())
+			}
+			if !strings.Contains(stdout.String(), tt.wantStdout) {
+				t.Errorf("run() stdout = %v, want %v", stdout.String(), tt.wantStdout)
+			}
+			if !strings.Contains(stderr.String(), tt.wantStderr) {
+				t.Errorf("run() stderr = %v, want %v", stderr.String(), tt.wantStderr)
+			}
+		})
+	}
+}
+
+func TestParseInterspersed(t *testing.T) {
+	type args struct {
+		args []string
+	}
+	tests := []struct {
+		name     string
+		args     args
+		want     []string
+		wantFlag string
+		wantErr  bool
+	}{
+		{
+			name:     "Flags after positional arguments are parsed",
+			args:     args{args: []string{"Design", "review", "--at", "tomorrow 14:00"}},
+			want:     []string{"Design", "review"},
+			wantFlag: "tomorrow 14:00",
+		},
+		{
+			name:     "Flags before positional arguments are parsed",
+			args:     args{args: []string{"--at", "9:00", "Standup"}},
+			want:     []string{"Standup"},
+			wantFlag: "9:00",
+		},
+		{
+			name: "Arguments after -- are positional",
+			args: args{args: []string{"--", "--at", "9:00"}},
+			want: []string{"--at", "9:00"},
+		},
+		{
+			name:    "Unknown flags are an error",
+			args:    args{args: []string{"Standup", "--bogus"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			at := fs.String("at", "", "")
+			got, err := parseInterspersed(fs, tt.args.args)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseInterspersed() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("parseInterspersed() = %v, want %v", got, tt.want)
+			}
+			if *at != tt.wantFlag {
+				t.Errorf("parseInterspersed() --at = %v, want %v", *at, tt.wantFlag)
+			}
+		})
+	}
+}
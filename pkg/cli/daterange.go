@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/jiyeol-lee/gcli/pkg/util"
+)
+
+var dateLayout = "2006-01-02"
+
+// rangeFlags holds the flags selecting the days a command looks at
+type rangeFlags struct {
+	from     string
+	to       string
+	tomorrow bool
+	week     bool
+	days     int
+}
+
+// addRangeFlags function registers the date range flags on the flag set
+func addRangeFlags(fs *flag.FlagSet) *rangeFlags {
+	r := &rangeFlags{}
+	fs.StringVar(&r.from, "from", "", "start of the range, as YYYY-MM-DD or RFC3339 (default today)")
+	fs.StringVar(&r.to, "to", "", "end of the range, as YYYY-MM-DD (inclusive) or RFC3339 (default end of the --from day)")
+	fs.BoolVar(&r.tomorrow, "tomorrow", false, "show tomorrow")
+	fs.BoolVar(&r.week, "week", false, "show the current week, Monday to Sunday")
+	fs.IntVar(&r.days, "days", 0, "show `N` days starting today")
+
+	return r
+}
+
+// resolve method returns the [from, to) range selected by the flags
+func (r *rangeFlags) resolve(now time.Time) (time.Time, time.Time, error) {
+	selected := 0
+	for _, set := range []bool{r.from != "" || r.to != "", r.tomorrow, r.week, r.days != 0} {
+		if set {
+			selected++
+		}
+	}
+	if selected > 1 {
+		return time.Time{}, time.Time{}, fmt.Errorf("--from/--to, --tomorrow, --week and --days are mutually exclusive")
+	}
+
+	today := util.StartOfDay(now)
+
+	switch {
+	case r.tomorrow:
+		return today.AddDate(0, 0, 1), today.AddDate(0, 0, 2), nil
+
+	case r.week:
+		monday := util.StartOfWeek(now)
+		return monday, monday.AddDate(0, 0, 7), nil
+
+	case r.days != 0:
+		if r.days < 0 {
+			return time.Time{}, time.Time{}, fmt.Errorf("--days must be positive")
+		}
+		return today, today.AddDate(0, 0, r.days), nil
+	}
+
+	from := today
+	if r.from != "" {
+		t, err := parseDateFlag(r.from, false)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid --from: %w", err)
+		}
+		from = t
+	}
+
+	to := util.StartOfDay(from).AddDate(0, 0, 1)
+	if r.to != "" {
+		t, err := parseDateFlag(r.to, true)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid --to: %w", err)
+		}
+		to = t
+	}
+
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, fmt.Errorf("--from must be before --to")
+	}
+
+	return from, to, nil
+}
+
+// parseDateFlag function parses a YYYY-MM-DD date in local time or an RFC3339
+// time. When end is set, a date means the end of that day.
+func parseDateFlag(s string, end bool) (time.Time, error) {
+	if t, err := time.ParseInLocation(dateLayout, s, time.Local); err == nil {
+		if end {
+			return t.AddDate(0, 0, 1), nil
+		}
+		return t, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is neither YYYY-MM-DD nor RFC3339", s)
+	}
+
+	return t, nil
+}
@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRangeFlagsResolve(t *testing.T) {
+	now := time.Date(2025, 3, 12, 15, 30, 0, 0, time.Local)
+	day := func(d int) time.Time {
+		return time.Date(2025, 3, d, 0, 0, 0, 0, time.Local)
+	}
+	tests := []struct {
+		name     string
+		flags    rangeFlags
+		wantFrom time.Time
+		wantTo   time.Time
+		wantErr  bool
+	}{
+		{
+			name:     "When no flag is set, return today",
+			flags:    rangeFlags{},
+			wantFrom: day(12),
+			wantTo:   day(13),
+		},
+		{
+			name:     "When --tomorrow is set, return tomorrow",
+			flags:    rangeFlags{tomorrow: true},
+			wantFrom: day(13),
+			wantTo:   day(14),
+		},
+		{
+			name:     "When --week is set, return Monday to Sunday",
+			flags:    rangeFlags{week: true},
+			wantFrom: day(10),
+			wantTo:   day(17),
+		},
+		{
+			name:     "When --days is set, return N days starting today",
+			flags:    rangeFlags{days: 3},
+			wantFrom: day(12),
+			wantTo:   day(15),
+		},
+		{
+			name:     "When --to is a date, it is inclusive",
+			flags:    rangeFlags{from: "2025-03-01", to: "2025-03-02"},
+			wantFrom: day(1),
+			wantTo:   day(3),
+		},
+		{
+			name:     "When only --from is set, return that day",
+			flags:    rangeFlags{from: "2025-03-20"},
+			wantFrom: day(20),
+			wantTo:   day(21),
+		},
+		{
+			name:     "RFC3339 times are used as is",
+			flags:    rangeFlags{from: "2025-03-01T10:00:00Z", to: "2025-03-01T12:00:00Z"},
+			wantFrom: time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC),
+			wantTo:   time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "When --from is after --to, return error",
+			flags:   rangeFlags{from: "2025-03-05", to: "2025-03-01"},
+			wantErr: true,
+		},
+		{
+			name:    "When date is invalid, return error",
+			flags:   rangeFlags{from: "next year"},
+			wantErr: true,
+		},
+		{
+			name:    "When several ranges are set, return error",
+			flags:   rangeFlags{tomorrow: true, days: 2},
+			wantErr: true,
+		},
+		{
+			name:    "When --days is negative, return error",
+			flags:   rangeFlags{days: -1},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, err := tt.flags.resolve(now)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("resolve() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !from.Equal(tt.wantFrom) || !to.Equal(tt.wantTo) {
+				t.Errorf("resolve() = %v - %v, want %v - %v", from, to, tt.wantFrom, tt.wantTo)
+			}
+		})
+	}
+}
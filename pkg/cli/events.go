@@ -0,0 +1,289 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"text/template"
+	"time"
+
+	"github.com/jiyeol-lee/gcli/pkg/config"
+	"github.com/jiyeol-lee/gcli/pkg/util"
+)
+
+var defaultMaxOutputLength = 20
+
+// listEvents function returns the events of the calendars in the [from, to)
+// range in their output form, merged and sorted by start time, optionally
+// leaving out the events of the work tracker
+func listEvents(
+	stores storeResolver,
+	calendars []config.CalendarConfig,
+	from, to, now time.Time,
+	skipWork bool,
+) ([]Event, error) {
+	events := []Event{}
+	for _, cal := range calendars {
+		store, err := stores(cal.Profile)
+		if err != nil {
+			return nil, err
+		}
+		c := newCalendar(store, cal.Id, now)
+
+		evts, err := c.GetEvents(from, to)
+		if err != nil {
+			return nil, fmt.Errorf("unable to retrieve events of %v: %w", cal.Name(), err)
+		}
+
+		for _, item := range evts.Items {
+			if item.Start == nil || item.End == nil {
+				continue
+			}
+			if skipWork && (c.GetWorkingHoursProperty(item) != "" || c.GetTotalWorkingHoursProperty(item) != "") {
+				continue
+			}
+
+			evt, err := newEvent(item, now)
+			if err != nil {
+				return nil, err
+			}
+			evt.Calendar = cal.Id
+			evt.CalendarName = cal.Name()
+			events = append(events, evt)
+		}
+	}
+
+	// Events of a single calendar are already sorted, this merges the calendars
+	slices.SortStableFunc(events, func(a, b Event) int {
+		if cmp := a.start.Compare(b.start); cmp != 0 {
+			return cmp
+		}
+
+		if a.AllDay && !b.AllDay {
+			return -1
+		}
+
+		if !a.AllDay && b.AllDay {
+			return 1
+		}
+
+		return 0
+	})
+
+	return events, nil
+}
+
+// calendarPrefix function returns the label printed before the events when
+// several calendars are read
+func calendarPrefix(calendars []config.CalendarConfig, evt Event) string {
+	if len(calendars) < 2 {
+		return ""
+	}
+
+	return evt.CalendarName + ": "
+}
+
+// todayEvents function returns the events of today, leaving out the events
+// of the work tracker
+func todayEvents(stores storeResolver, calendars []config.CalendarConfig, now time.Time) ([]Event, error) {
+	today := util.StartOfDay(now)
+
+	return listEvents(stores, calendars, today, today.AddDate(0, 0, 1), now, true)
+}
+
+// nextEvent function returns the first timed event starting after now, if any
+func nextEvent(events []Event, now time.Time) []Event {
+	for _, evt := range events {
+		if !evt.AllDay && evt.start.After(now) {
+			return []Event{evt}
+		}
+	}
+
+	return []Event{}
+}
+
+// currentEvent function returns the first timed event in progress, if any
+func currentEvent(events []Event, now time.Time) []Event {
+	for _, evt := range events {
+		if !evt.AllDay && evt.start.Before(now) && evt.end.After(now) {
+			return []Event{evt}
+		}
+	}
+
+	return []Event{}
+}
+
+func newListCommand() *Command {
+	cmd := &Command{
+		Name:  "list",
+		Short: "List the events of today or of a date range",
+		Flags: flag.NewFlagSet("list", flag.ContinueOnError),
+	}
+	dates := addRangeFlags(cmd.Flags)
+
+	cmd.Run = func(env *Env, args []string) error {
+		if err := noArgs(cmd, args); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		from, to, err := dates.resolve(now)
+		if err != nil {
+			return &usageError{cmd: cmd, msg: err.Error()}
+		}
+
+		calendars := env.Calendars()
+		events, err := listEvents(env.Store, calendars, from, to, now, false)
+		if err != nil {
+			return err
+		}
+
+		multiDay := to.After(from.AddDate(0, 0, 1))
+		return env.render(events, func(w io.Writer) error {
+			for _, evt := range events {
+				if evt.AllDay {
+					continue
+				}
+
+				if multiDay {
+					fmt.Fprintf(w, "%v ", evt.start.Local().Format("Mon 01-02"))
+				}
+				fmt.Fprintf(
+					w,
+					"%v%v (%v - %v)\n",
+					calendarPrefix(calendars, evt),
+					evt.Summary,
+					evt.start.Local().Format("15:04"),
+					evt.end.Local().Format("15:04"),
+				)
+			}
+
+			return nil
+		})
+	}
+
+	return cmd
+}
+
+// writeStatus function writes the event found by a status-bar command, with
+// the template when given, else with text, or for the status bar of --bar
+func writeStatus(
+	w io.Writer,
+	env *Env,
+	status *statusFlags,
+	tmpl *template.Template,
+	found []Event,
+	events []Event,
+	now time.Time,
+	text func(evt Event) (string, string),
+) error {
+	var line, short string
+	if len(found) == 0 {
+		line = status.emptyText(env)
+	} else {
+		line, short = text(found[0])
+		if tmpl != nil {
+			var err error
+			if line, err = executeTemplate(tmpl, found[0], now); err != nil {
+				return err
+			}
+		}
+	}
+
+	if status.bar != "" {
+		return newBarStatus(line, short, events, now).write(w, status.bar)
+	}
+	if len(found) == 0 {
+		_, err := fmt.Fprint(w, line)
+		return err
+	}
+	_, err := fmt.Fprintln(w, line)
+
+	return err
+}
+
+func newSoonCommand() *Command {
+	cmd := &Command{
+		Name:  "soon",
+		Short: "Show the next event starting today",
+		Flags: flag.NewFlagSet("soon", flag.ContinueOnError),
+	}
+	status := addStatusFlags(cmd.Flags)
+
+	cmd.Run = func(env *Env, args []string) error {
+		if err := noArgs(cmd, args); err != nil {
+			return err
+		}
+
+		tmpl, err := status.prepare(env, cmd)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		calendars := env.Calendars()
+		events, err := todayEvents(env.Store, calendars, now)
+		if err != nil {
+			return err
+		}
+		found := nextEvent(events, now)
+
+		return env.render(found, func(w io.Writer) error {
+			return writeStatus(w, env, status, tmpl, found, events, now, func(evt Event) (string, string) {
+				line := fmt.Sprintf(
+					"%v[%v] in %vmin",
+					calendarPrefix(calendars, evt),
+					util.TruncateWithSuffix(evt.Summary, status.maxLength),
+					evt.MinutesUntil,
+				)
+				return line, fmt.Sprintf("in %vmin", evt.MinutesUntil)
+			})
+		})
+	}
+
+	return cmd
+}
+
+func newInProgressCommand() *Command {
+	cmd := &Command{
+		Name:  "in-progress",
+		Short: "Show the event in progress",
+		Flags: flag.NewFlagSet("in-progress", flag.ContinueOnError),
+	}
+	status := addStatusFlags(cmd.Flags)
+
+	cmd.Run = func(env *Env, args []string) error {
+		if err := noArgs(cmd, args); err != nil {
+			return err
+		}
+
+		tmpl, err := status.prepare(env, cmd)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		calendars := env.Calendars()
+		events, err := todayEvents(env.Store, calendars, now)
+		if err != nil {
+			return err
+		}
+		found := currentEvent(events, now)
+
+		return env.render(found, func(w io.Writer) error {
+			return writeStatus(w, env, status, tmpl, found, events, now, func(evt Event) (string, string) {
+				line := fmt.Sprintf(
+					"%v[%v] (%v-%v)",
+					calendarPrefix(calendars, evt),
+					util.TruncateWithSuffix(evt.Summary, status.maxLength),
+					evt.start.Local().Format("15:04"),
+					evt.end.Local().Format("15:04"),
+				)
+				return line, "until " + evt.end.Local().Format("15:04")
+			})
+		})
+	}
+
+	return cmd
+}
@@ -0,0 +1,226 @@
+package cli
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jiyeol-lee/gcli/pkg/config"
+	"github.com/jiyeol-lee/gcli/pkg/gcal"
+	"google.golang.org/api/calendar/v3"
+)
+
+var primary = []config.CalendarConfig{{Id: "primary"}}
+
+func newTestStore(t *testing.T, day time.Time) *gcal.MemoryStore {
+	t.Helper()
+
+	at := func(h, m int) string {
+		return day.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute).Format(time.RFC3339)
+	}
+	store := gcal.NewMemoryStore()
+	for _, e := range []*calendar.Event{
+		{Summary: "Holiday", Start: &calendar.EventDateTime{Date: day.Format("2006-01-02")}, End: &calendar.EventDateTime{Date: day.AddDate(0, 0, 1).Format("2006-01-02")}},
+		{Summary: "Standup", Start: &calendar.EventDateTime{DateTime: at(9, 30)}, End: &calendar.EventDateTime{DateTime: at(9, 45)}},
+		{Summary: "Lunch", Start: &calendar.EventDateTime{DateTime: at(12, 0)}, End: &calendar.EventDateTime{DateTime: at(13, 0)}},
+		{
+			Summary: "Working",
+			Start:   &calendar.EventDateTime{DateTime: at(9, 0)},
+			End:     &calendar.EventDateTime{DateTime: at(17, 0)},
+			ExtendedProperties: &calendar.EventExtendedProperties{
+				Private: map[string]string{"WORKING_HOURS": "8.000"},
+			},
+		},
+	} {
+		if _, err := store.Insert("primary", e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return store
+}
+
+func TestNextEvent(t *testing.T) {
+	day := time.Date(2025, 3, 12, 0, 0, 0, 0, time.Local)
+	store := newTestStore(t, day)
+	tests := []struct {
+		name             string
+		now              time.Time
+		want             string
+		wantMinutesUntil int
+	}{
+		{
+			name:             "Next timed event is returned, work sessions are skipped",
+			now:              day.Add(time.Hour * 8),
+			want:             "Standup",
+			wantMinutesUntil: 90,
+		},
+		{
+			name:             "Events in progress are skipped",
+			now:              day.Add(time.Hour*9 + time.Minute*40),
+			want:             "Lunch",
+			wantMinutesUntil: 140,
+		},
+		{
+			name: "When no event is left, return nothing",
+			now:  day.Add(time.Hour * 15),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := todayEvents(singleStore(store), primary, tt.now)
+			if err != nil {
+				t.Errorf("todayEvents() error = %v", err)
+				return
+			}
+			got := nextEvent(events, tt.now)
+			if tt.want == "" {
+				if len(got) != 0 {
+					t.Errorf("nextEvent() = %+v, want none", got)
+				}
+				return
+			}
+			if len(got) != 1 || got[0].Summary != tt.want || got[0].MinutesUntil != tt.wantMinutesUntil {
+				t.Errorf("nextEvent() = %+v, want %v in %v minutes", got, tt.want, tt.wantMinutesUntil)
+			}
+		})
+	}
+}
+
+func TestCurrentEvent(t *testing.T) {
+	day := time.Date(2025, 3, 12, 0, 0, 0, 0, time.Local)
+	store := newTestStore(t, day)
+	tests := []struct {
+		name string
+		now  time.Time
+		want string
+	}{
+		{
+			name: "Timed event in progress is returned, all-day events and work sessions are skipped",
+			now:  day.Add(time.Hour*12 + time.Minute*30),
+			want: "Lunch",
+		},
+		{
+			name: "When no event is in progress, return nothing",
+			now:  day.Add(time.Hour * 11),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := todayEvents(singleStore(store), primary, tt.now)
+			if err != nil {
+				t.Errorf("todayEvents() error = %v", err)
+				return
+			}
+			got := currentEvent(events, tt.now)
+			if tt.want == "" {
+				if len(got) != 0 {
+					t.Errorf("currentEvent() = %+v, want none", got)
+				}
+				return
+			}
+			if len(got) != 1 || got[0].Summary != tt.want {
+				t.Errorf("currentEvent() = %+v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestListEventsAcrossCalendars(t *testing.T) {
+	day := time.Date(2025, 3, 12, 0, 0, 0, 0, time.Local)
+	store := newTestStore(t, day)
+	store.Insert("team@example.com", &calendar.Event{
+		Summary: "Retro",
+		Start:   &calendar.EventDateTime{DateTime: day.Add(time.Hour * 10).Format(time.RFC3339)},
+		End:     &calendar.EventDateTime{DateTime: day.Add(time.Hour * 11).Format(time.RFC3339)},
+	})
+	calendars := []config.CalendarConfig{{Id: "primary"}, {Id: "team@example.com", Label: "team"}}
+
+	got, err := listEvents(singleStore(store), calendars, day, day.AddDate(0, 0, 1), day, true)
+	if err != nil {
+		t.Fatalf("listEvents() error = %v", err)
+	}
+
+	want := []string{"primary: Holiday", "primary: Standup", "team: Retro", "primary: Lunch"}
+	if len(got) != len(want) {
+		t.Fatalf("listEvents() = %+v, want %v", got, want)
+	}
+	for i, evt := range got {
+		if label := calendarPrefix(calendars, evt) + evt.Summary; label != want[i] {
+			t.Errorf("listEvents()[%v] = %v, want %v", i, label, want[i])
+		}
+	}
+}
+
+func TestWriteStatus(t *testing.T) {
+	day := time.Date(2025, 3, 12, 0, 0, 0, 0, time.Local)
+	store := newTestStore(t, day)
+	text := func(evt Event) (string, string) {
+		return fmt.Sprintf("[%v] in %vmin", evt.Summary, evt.MinutesUntil), fmt.Sprintf("in %vmin", evt.MinutesUntil)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		now  time.Time
+		want string
+	}{
+		{
+			name: "The event is written with the text of the command",
+			now:  day.Add(9*time.Hour + 20*time.Minute),
+			want: "[Standup] in 10min\n",
+		},
+		{
+			name: "The event is written with the template given to --format",
+			args: []string{"--format", "{{.Summary}} at {{clock .Start}}"},
+			now:  day.Add(9*time.Hour + 20*time.Minute),
+			want: "Standup at 09:30\n",
+		},
+		{
+			name: "When no event is found, the empty text is written without a newline",
+			args: []string{"--empty", "-"},
+			now:  day.Add(15 * time.Hour),
+			want: "-",
+		},
+		{
+			name: "The event is written for the status bar given to --bar",
+			args: []string{"--bar", "polybar"},
+			now:  day.Add(9*time.Hour + 20*time.Minute),
+			want: "%{F#ffb86c}[Standup] in 10min%{F-}\n",
+		},
+		{
+			name: "When no event is found, the empty text is written for the status bar",
+			args: []string{"--bar", "i3blocks"},
+			now:  day.Add(15 * time.Hour),
+			want: "N/A\n\n\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &Command{Name: "soon", Flags: flag.NewFlagSet("soon", flag.ContinueOnError)}
+			status := addStatusFlags(cmd.Flags)
+			if err := cmd.Flags.Parse(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			env := &Env{Config: &config.Config{}}
+			tmpl, err := status.prepare(env, cmd)
+			if err != nil {
+				t.Fatalf("prepare() error = %v", err)
+			}
+
+			events, err := todayEvents(singleStore(store), primary, tt.now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var buf bytes.Buffer
+			if err := writeStatus(&buf, env, status, tmpl, nextEvent(events, tt.now), events, tt.now, text); err != nil {
+				t.Fatalf("writeStatus() error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("writeStatus() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/jiyeol-lee/gcli/pkg/util"
+)
+
+// EventTemplate is the data given to the --format template of the status-bar
+// commands, e.g. --format '{{truncate 15 .Summary}} in {{duration .MinutesUntil}}'.
+//
+//	.Summary       event title
+//	.Start, .End   local start and end, as time.Time
+//	.MinutesUntil  minutes from now until the start, rounded, negative once started
+//	.MinutesLeft   whole minutes from now until the end
+//	.Location      free-form location, empty if unset
+//	.MeetLink      video meeting link, empty if unset
+//	.Calendar      configured label of the calendar, or its id
+//
+// On top of the text/template builtins, the template can call:
+//
+//	truncate N S  S cut to N characters followed by "...", if longer
+//	duration M    M minutes as e.g. "1h05m" or "45m"
+//	clock T       local time of day of T, as 15:04
+type EventTemplate struct {
+	Summary      string
+	Start        time.Time
+	End          time.Time
+	MinutesUntil int
+	MinutesLeft  int
+	Location     string
+	MeetLink     string
+	Calendar     string
+}
+
+var templateFuncs = template.FuncMap{
+	"truncate": func(n int, s string) string {
+		return util.TruncateWithSuffix(s, n)
+	},
+	"duration": func(minutes int) string {
+		return util.FormatDuration(time.Duration(minutes) * time.Minute)
+	},
+	"clock": func(t time.Time) string {
+		return t.Local().Format("15:04")
+	},
+}
+
+// statusFlags holds the flags of the status-bar commands shaping their text output
+type statusFlags struct {
+	fs        *flag.FlagSet
+	format    string
+	empty     string
+	bar       string
+	maxLength int
+}
+
+// addStatusFlags function registers the flags of the status-bar commands
+func addStatusFlags(fs *flag.FlagSet) *statusFlags {
+	f := &statusFlags{fs: fs}
+	fs.IntVar(&f.maxLength, "max-length", defaultMaxOutputLength, "truncate the summary to this many characters")
+	fs.StringVar(&f.format, "format", "", "print the event with the Go `template`, see 'go doc github.com/jiyeol-lee/gcli/pkg/cli EventTemplate'")
+	fs.StringVar(&f.empty, "empty", "", "print `text` when there is no event (default the configured one, or \"N/A\")")
+	fs.StringVar(&f.bar, "bar", "", "print for the status `bar`: waybar, i3blocks, polybar or tmux")
+
+	return f
+}
+
+// prepare method checks the flags and returns the template given to --format,
+// or else configured for the command, nil when there is none
+func (f *statusFlags) prepare(env *Env, cmd *Command) (*template.Template, error) {
+	if f.bar != "" && !slices.Contains(barFormats, f.bar) {
+		return nil, &usageError{cmd: cmd, msg: fmt.Sprintf("unknown status bar %q, want waybar, i3blocks, polybar or tmux", f.bar)}
+	}
+
+	text := f.format
+	if text == "" {
+		text = env.Config.GetFormat(cmd.Name)
+	}
+	if text == "" {
+		return nil, nil
+	}
+
+	tmpl, err := template.New(cmd.Name).Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, &usageError{cmd: cmd, msg: fmt.Sprintf("invalid --format: %v", err)}
+	}
+
+	return tmpl, nil
+}
+
+// emptyText method returns what to print when there is no event
+func (f *statusFlags) emptyText(env *Env) string {
+	set := false
+	f.fs.Visit(func(fl *flag.Flag) {
+		if fl.Name == "empty" {
+			set = true
+		}
+	})
+	if set {
+		return f.empty
+	}
+
+	return env.Config.GetEmpty()
+}
+
+// executeTemplate function returns the event rendered with the template
+func executeTemplate(tmpl *template.Template, evt Event, now time.Time) (string, error) {
+	data := EventTemplate{
+		Summary:      evt.Summary,
+		Start:        evt.start.Local(),
+		End:          evt.end.Local(),
+		MinutesUntil: evt.MinutesUntil,
+		MinutesLeft:  int(evt.end.Sub(now).Minutes()),
+		Location:     evt.Location,
+		MeetLink:     evt.MeetLink,
+		Calendar:     evt.CalendarName,
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("unable to render --format: %w", err)
+	}
+
+	return b.String(), nil
+}
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"flag"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/jiyeol-lee/gcli/pkg/config"
+)
+
+func TestExecuteTemplate(t *testing.T) {
+	now := time.Date(2025, 3, 12, 9, 50, 0, 0, time.Local)
+	evt := Event{
+		Summary:      "Quarterly planning review",
+		MinutesUntil: 70,
+		Location:     "Room 1",
+		MeetLink:     "https://meet.google.com/abc-defg-hij",
+		CalendarName: "work",
+		start:        time.Date(2025, 3, 12, 11, 0, 0, 0, time.Local),
+		end:          time.Date(2025, 3, 12, 11, 30, 0, 0, time.Local),
+	}
+
+	tests := []struct {
+		name   string
+		format string
+		want   string
+	}{
+		{
+			name:   "Fields are rendered",
+			format: "{{.Calendar}}: {{.Summary}} @ {{.Location}} {{.MeetLink}}",
+			want:   "work: Quarterly planning review @ Room 1 https://meet.google.com/abc-defg-hij",
+		},
+		{
+			name:   "Helpers truncate and format times",
+			format: "[{{truncate 9 .Summary}}] in {{duration .MinutesUntil}} ({{clock .Start}}-{{.End.Format \"15:04\"}}, {{.MinutesLeft}}min left)",
+			want:   "[Quarterly...] in 1h10m (11:00-11:30, 100min left)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := template.Must(template.New("test").Funcs(templateFuncs).Parse(tt.format))
+
+			got, err := executeTemplate(tmpl, evt, now)
+			if err != nil {
+				t.Fatalf("executeTemplate() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("executeTemplate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatusFlagsEmptyText(t *testing.T) {
+	blank := ""
+	tests := []struct {
+		name string
+		args []string
+		cfg  *config.Config
+		want string
+	}{
+		{
+			name: "When nothing is set, print N/A",
+			cfg:  &config.Config{},
+			want: "N/A",
+		},
+		{
+			name: "When configured, print the configured text",
+			cfg:  &config.Config{Empty: &blank},
+			want: "",
+		},
+		{
+			name: "When --empty is given, it wins over the configuration",
+			args: []string{"--empty", "-"},
+			cfg:  &config.Config{Empty: &blank},
+			want: "-",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			f := addStatusFlags(fs)
+			if err := fs.Parse(tt.args); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := f.emptyText(&Env{Config: tt.cfg}); got != tt.want {
+				t.Errorf("emptyText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
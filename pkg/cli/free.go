@@ -0,0 +1,224 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jiyeol-lee/gcli/pkg/config"
+	"github.com/jiyeol-lee/gcli/pkg/gcal"
+	"github.com/jiyeol-lee/gcli/pkg/util"
+)
+
+// slotStep is the granularity of the start of the free slots
+var slotStep = 5 * time.Minute
+
+// FreeSlot is the machine-readable form of a free slot printed by the free
+// and schedule commands.
+//
+//	start, end  RFC3339 bounds of the slot
+//	minutes     length of the slot
+type FreeSlot struct {
+	Start   string `json:"start"`
+	End     string `json:"end"`
+	Minutes int    `json:"minutes"`
+}
+
+// newFreeSlot function returns the output form of a free period
+func newFreeSlot(p gcal.Period) FreeSlot {
+	return FreeSlot{
+		Start:   p.Start.Format(time.RFC3339),
+		End:     p.End.Format(time.RFC3339),
+		Minutes: int(p.End.Sub(p.Start).Minutes()),
+	}
+}
+
+// workHours are the hours of the day, as offsets from midnight, available on
+// the days set
+type workHours struct {
+	start time.Duration
+	end   time.Duration
+	days  [7]bool
+}
+
+// workHoursOf function returns the configured working hours, on the days with
+// a work target, or on every day with anyDay
+func workHoursOf(cfg *config.Config, anyDay bool) (workHours, error) {
+	var hours workHours
+	var err error
+	if hours.start, hours.end, err = cfg.GetWorkHours(); err != nil {
+		return workHours{}, err
+	}
+
+	daily, _, err := cfg.GetWorkTargets()
+	if err != nil {
+		return workHours{}, err
+	}
+	for day, target := range daily {
+		hours.days[day] = anyDay || target > 0
+	}
+
+	return hours, nil
+}
+
+// windows method returns the periods of the working hours within the [from, to)
+// days, the hours being read in loc
+func (h workHours) windows(from, to time.Time, loc *time.Location) []gcal.Period {
+	clock := func(day time.Time, offset time.Duration) time.Time {
+		return time.Date(day.Year(), day.Month(), day.Day(), int(offset.Hours()), int(offset.Minutes())%60, 0, 0, loc)
+	}
+
+	var windows []gcal.Period
+	// A day before and after, the days of loc not lining up with the range
+	for d := util.StartOfDay(from.In(loc)).AddDate(0, 0, -1); d.Before(to.AddDate(0, 0, 1)); d = d.AddDate(0, 0, 1) {
+		if !h.days[d.Weekday()] {
+			continue
+		}
+
+		start, end := util.MaxTime(clock(d, h.start), from), util.MinTime(clock(d, h.end), to)
+		if start.Before(end) {
+			windows = append(windows, gcal.Period{Start: start, End: end})
+		}
+	}
+
+	return windows
+}
+
+// freeSlots function returns the free periods of at least duration within the
+// windows, from now on, kept at least buffer away from the busy periods
+func freeSlots(windows, busy []gcal.Period, now time.Time, duration, buffer time.Duration) []gcal.Period {
+	padded := make([]gcal.Period, 0, len(busy))
+	for _, b := range busy {
+		padded = append(padded, gcal.Period{Start: b.Start.Add(-buffer), End: b.End.Add(buffer)})
+	}
+	padded = gcal.MergePeriods(padded)
+
+	// Slots start on the next step rather than at the odd minute of now
+	earliest := now.Truncate(slotStep)
+	if earliest.Before(now) {
+		earliest = earliest.Add(slotStep)
+	}
+
+	slots := []gcal.Period{}
+	for _, w := range windows {
+		cursor := util.MaxTime(w.Start, earliest)
+		for _, b := range padded {
+			if !b.End.After(cursor) || !b.Start.Before(w.End) {
+				continue
+			}
+			if b.Start.Sub(cursor) >= duration {
+				slots = append(slots, gcal.Period{Start: cursor, End: b.Start})
+			}
+			cursor = b.End
+		}
+		if w.End.Sub(cursor) >= duration {
+			slots = append(slots, gcal.Period{Start: cursor, End: w.End})
+		}
+	}
+
+	return slots
+}
+
+// calendarsBusy function returns the merged busy periods of the calendars
+// overlapping the [from, to) range
+func calendarsBusy(stores storeResolver, calendars []config.CalendarConfig, from, to, now time.Time) ([]gcal.Period, error) {
+	var busy []gcal.Period
+	for _, cal := range calendars {
+		store, err := stores(cal.Profile)
+		if err != nil {
+			return nil, err
+		}
+
+		periods, err := newCalendar(store, cal.Id, now).GetBusy(from, to)
+		if err != nil {
+			return nil, fmt.Errorf("unable to retrieve the busy times of %v: %w", cal.Name(), err)
+		}
+		busy = append(busy, periods...)
+	}
+
+	return gcal.MergePeriods(busy), nil
+}
+
+// writeSlots function writes the slots a line each, e.g.
+// "Thu 03-13 09:00-11:30 (2h30m)"
+func writeSlots(w io.Writer, slots []FreeSlot, none string) {
+	if len(slots) == 0 {
+		fmt.Fprintln(w, none)
+		return
+	}
+
+	for _, slot := range slots {
+		fmt.Fprintf(
+			w,
+			"%v-%v (%v)\n",
+			localDateTime(slot.Start),
+			localClock(slot.End),
+			util.FormatDuration(time.Duration(slot.Minutes)*time.Minute),
+		)
+	}
+}
+
+func newFreeCommand() *Command {
+	cmd := &Command{
+		Name:  "free",
+		Short: "Find free slots in the calendars",
+		Long: "Find the free slots of at least --duration within the working hours of the days\n" +
+			"given to --within, e.g. \"tomorrow\", \"fri\", \"next week\" or \"3 days\". The working\n" +
+			"hours are the configured ones, 09:00-18:00 by default, on the days with a work\n" +
+			"target. Transparent events, such as work sessions, and declined invitations do not\n" +
+			"take time.",
+		Flags: flag.NewFlagSet("free", flag.ContinueOnError),
+	}
+	duration := cmd.Flags.String("duration", "30m", "find slots of at least this `duration`, e.g. 1h or 45m")
+	within := cmd.Flags.String("within", "today", "search these `days`, e.g. tomorrow, fri, \"next week\" or \"3 days\"")
+	between := cmd.Flags.String("between", "", "search between these `hours`, e.g. 09:00-18:00 (default the configured working hours)")
+	buffer := cmd.Flags.String("buffer", "0", "keep this `duration` free around meetings, e.g. 10m")
+	anyDay := cmd.Flags.Bool("any-day", false, "search the days without a work target too")
+
+	cmd.Run = func(env *Env, args []string) error {
+		if err := noArgs(cmd, args); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		d, err := util.ParseHumanDuration(*duration)
+		if err != nil || d <= 0 {
+			return &usageError{cmd: cmd, msg: fmt.Sprintf("invalid --duration %q", *duration)}
+		}
+		buf, err := util.ParseHumanDuration(*buffer)
+		if err != nil || buf < 0 {
+			return &usageError{cmd: cmd, msg: fmt.Sprintf("invalid --buffer %q", *buffer)}
+		}
+		from, to, err := util.ParseHumanRange(*within, now)
+		if err != nil {
+			return &usageError{cmd: cmd, msg: fmt.Sprintf("invalid --within: %v", err)}
+		}
+		hours, err := workHoursOf(env.Config, *anyDay)
+		if err != nil {
+			return err
+		}
+		if *between != "" {
+			if hours.start, hours.end, err = util.ParseClockRange(*between); err != nil {
+				return &usageError{cmd: cmd, msg: fmt.Sprintf("invalid --between: %v", err)}
+			}
+		}
+
+		busy, err := calendarsBusy(env.Store, env.Calendars(), from, to, now)
+		if err != nil {
+			return err
+		}
+
+		slots := []FreeSlot{}
+		for _, p := range freeSlots(hours.windows(from, to, time.Local), busy, now, d, buf) {
+			slots = append(slots, newFreeSlot(p))
+		}
+
+		return env.render(slots, func(w io.Writer) error {
+			writeSlots(w, slots, "No free slot")
+			return nil
+		})
+	}
+
+	return cmd
+}
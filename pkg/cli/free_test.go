@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jiyeol-lee/gcli/pkg/gcal"
+	"google.golang.org/api/calendar/v3"
+)
+
+func TestFreeSlots(t *testing.T) {
+	day := time.Date(2025, 3, 12, 0, 0, 0, 0, time.Local)
+	at := func(h, m int) time.Time {
+		return day.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute)
+	}
+	store := gcal.NewMemoryStore()
+	for _, e := range []*calendar.Event{
+		{Summary: "Holiday", Transparency: "transparent", Start: &calendar.EventDateTime{Date: "2025-03-12"}, End: &calendar.EventDateTime{Date: "2025-03-13"}},
+		{Summary: "Working", Transparency: "transparent", Start: &calendar.EventDateTime{DateTime: at(9, 0).Format(time.RFC3339)}, End: &calendar.EventDateTime{DateTime: at(17, 0).Format(time.RFC3339)}},
+		{Summary: "Standup", Start: &calendar.EventDateTime{DateTime: at(9, 30).Format(time.RFC3339)}, End: &calendar.EventDateTime{DateTime: at(9, 45).Format(time.RFC3339)}},
+		{Summary: "Lunch", Start: &calendar.EventDateTime{DateTime: at(12, 0).Format(time.RFC3339)}, End: &calendar.EventDateTime{DateTime: at(13, 0).Format(time.RFC3339)}},
+		{Summary: "Review", Start: &calendar.EventDateTime{DateTime: at(15, 0).Format(time.RFC3339)}, End: &calendar.EventDateTime{DateTime: at(16, 0).Format(time.RFC3339)}},
+	} {
+		if _, err := store.Insert("primary", e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	busy, err := calendarsBusy(singleStore(store), primary, day, day.AddDate(0, 0, 1), day)
+	if err != nil {
+		t.Fatalf("calendarsBusy() error = %v", err)
+	}
+	hours := workHours{start: 9 * time.Hour, end: 18 * time.Hour, days: [7]bool{false, true, true, true, true, true, false}}
+
+	tests := []struct {
+		name     string
+		now      time.Time
+		duration time.Duration
+		buffer   time.Duration
+		want     []gcal.Period
+	}{
+		{
+			name:     "Gaps of the working hours long enough are free, transparent events are not busy",
+			now:      at(7, 0),
+			duration: time.Hour,
+			want: []gcal.Period{
+				{Start: at(9, 45), End: at(12, 0)},
+				{Start: at(13, 0), End: at(15, 0)},
+				{Start: at(16, 0), End: at(18, 0)},
+			},
+		},
+		{
+			name:     "Buffers are kept around meetings",
+			now:      at(7, 0),
+			duration: time.Hour + 45*time.Minute,
+			buffer:   10 * time.Minute,
+			want: []gcal.Period{
+				{Start: at(9, 55), End: at(11, 50)},
+				{Start: at(16, 10), End: at(18, 0)},
+			},
+		},
+		{
+			name:     "Slots start on the next step after now",
+			now:      at(16, 52),
+			duration: time.Hour,
+			want: []gcal.Period{
+				{Start: at(16, 55), End: at(18, 0)},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := freeSlots(hours.windows(day, day.AddDate(0, 0, 1), time.Local), busy, tt.now, tt.duration, tt.buffer)
+			if !periodsEqual(got, tt.want) {
+				t.Errorf("freeSlots() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorkHoursWindows(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+	// Wednesday to Monday
+	from := time.Date(2025, 3, 12, 0, 0, 0, 0, newYork)
+	to := from.AddDate(0, 0, 6)
+	hours := workHours{start: 9 * time.Hour, end: 17 * time.Hour, days: [7]bool{false, true, true, true, true, true, false}}
+
+	var got []string
+	for _, w := range hours.windows(from, to, newYork) {
+		got = append(got, w.Start.Format("Mon 15:04")+"-"+w.End.Format("15:04"))
+	}
+	want := []string{"Wed 09:00-17:00", "Thu 09:00-17:00", "Fri 09:00-17:00", "Mon 09:00-17:00"}
+	if len(got) != len(want) {
+		t.Fatalf("windows() = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("windows() = %q, want %q", got, want)
+		}
+	}
+
+	// The hours of a colleague in Tokyo, 13 hours ahead of New York in March
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skip(err)
+	}
+	windows := hours.windows(from, from.AddDate(0, 0, 1), tokyo)
+	if len(windows) == 0 || !windows[0].Start.Equal(from) {
+		t.Errorf("windows() in Tokyo = %v, want the first one clamped to %v", windows, from)
+	}
+}
+
+// periodsEqual function reports whether the periods are the same instants,
+// whatever their locations
+func periodsEqual(a, b []gcal.Period) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Start.Equal(b[i].Start) || !a[i].End.Equal(b[i].End) {
+			return false
+		}
+	}
+
+	return true
+}
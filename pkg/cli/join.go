@@ -0,0 +1,133 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/jiyeol-lee/gcli/pkg/config"
+	"github.com/jiyeol-lee/gcli/pkg/util"
+)
+
+// joinEvent function returns the meeting to join: the last one started among
+// the meetings in progress, or else the next one starting within window. Only
+// timed events with a video meeting link are meetings. Whole days are listed,
+// the next one too when the window crosses midnight, so that the listing is
+// served from the cache whatever the time.
+func joinEvent(stores storeResolver, calendars []config.CalendarConfig, window time.Duration, now time.Time) (Event, error) {
+	from := util.StartOfDay(now)
+	to := util.StartOfDay(now.Add(window)).AddDate(0, 0, 1)
+	events, err := listEvents(stores, calendars, from, to, now, true)
+	if err != nil {
+		return Event{}, err
+	}
+
+	var current, next *Event
+	for i, evt := range events {
+		if evt.AllDay || evt.MeetLink == "" {
+			continue
+		}
+
+		switch {
+		case !evt.start.After(now) && evt.end.After(now):
+			if current == nil || !evt.start.Before(current.start) {
+				current = &events[i]
+			}
+		case evt.start.After(now) && evt.start.Sub(now) <= window && next == nil:
+			next = &events[i]
+		}
+	}
+
+	switch {
+	case current != nil:
+		return *current, nil
+	case next != nil:
+		return *next, nil
+	}
+
+	return Event{}, fmt.Errorf("no meeting in progress or starting within %v", window)
+}
+
+// openLink function opens the link with the opener command, given the link as
+// its last argument, or with the browser when the opener is empty
+func openLink(opener string, link string) error {
+	if opener == "" {
+		return util.OpenURL(link)
+	}
+
+	args := strings.Fields(opener)
+	if len(args) == 0 {
+		return errors.New("the opener is blank")
+	}
+
+	return exec.Command(args[0], append(args[1:], link)...).Start()
+}
+
+func newJoinCommand() *Command {
+	cmd := &Command{
+		Name:  "join",
+		Short: "Open the video link of the meeting in progress or about to start",
+		Long: "Open the video link of the meeting in progress, or else of the next one starting\n" +
+			"within the join window. The link is taken from the conference of the event, its\n" +
+			"Hangout link, or the first Zoom, Teams, Meet or Webex link of its location or\n" +
+			"description. It is opened with the configured opener, or else the browser.",
+		Flags: flag.NewFlagSet("join", flag.ContinueOnError),
+	}
+	window := cmd.Flags.Duration("window", 0, "join meetings starting within this `duration` (default the configured one, or 5m)")
+	opener := cmd.Flags.String("opener", "", "open the link with this `command` (default the configured one, or the browser)")
+	printOnly := cmd.Flags.Bool("print", false, "print the link instead of opening it")
+
+	cmd.Run = func(env *Env, args []string) error {
+		if err := noArgs(cmd, args); err != nil {
+			return err
+		}
+		if *window < 0 {
+			return &usageError{cmd: cmd, msg: "--window must not be negative"}
+		}
+
+		set := false
+		cmd.Flags.Visit(func(fl *flag.Flag) {
+			if fl.Name == "window" {
+				set = true
+			}
+		})
+		if !set {
+			w, err := env.Config.GetJoinWindow()
+			if err != nil {
+				return err
+			}
+			*window = w
+		}
+		if *opener == "" && env.Config != nil {
+			*opener = env.Config.Join.Opener
+		}
+
+		calendars := env.Calendars()
+		evt, err := joinEvent(env.Store, calendars, *window, time.Now())
+		if err != nil {
+			return err
+		}
+
+		if !*printOnly {
+			if err := openLink(*opener, evt.MeetLink); err != nil {
+				return fmt.Errorf("unable to open %v: %w", evt.MeetLink, err)
+			}
+		}
+
+		return env.render(evt, func(w io.Writer) error {
+			if *printOnly {
+				fmt.Fprintln(w, evt.MeetLink)
+				return nil
+			}
+
+			fmt.Fprintf(w, "Joining %v%v: %v\n", calendarPrefix(calendars, evt), evt.Summary, evt.MeetLink)
+			return nil
+		})
+	}
+
+	return cmd
+}
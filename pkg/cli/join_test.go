@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/jiyeol-lee/gcli/pkg/config"
+	"github.com/jiyeol-lee/gcli/pkg/gcal"
+	"google.golang.org/api/calendar/v3"
+)
+
+func TestJoinEvent(t *testing.T) {
+	day := time.Date(2025, 3, 12, 0, 0, 0, 0, time.Local)
+	store := newTestStore(t, day)
+	at := func(h, m int) time.Time {
+		return day.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute)
+	}
+	for _, e := range []*calendar.Event{
+		{
+			Summary:     "Workshop",
+			Start:       &calendar.EventDateTime{DateTime: at(13, 0).Format(time.RFC3339)},
+			End:         &calendar.EventDateTime{DateTime: at(17, 0).Format(time.RFC3339)},
+			HangoutLink: "https://meet.google.com/aaa-bbbb-ccc",
+		},
+		{
+			Summary:  "Sync",
+			Start:    &calendar.EventDateTime{DateTime: at(14, 0).Format(time.RFC3339)},
+			End:      &calendar.EventDateTime{DateTime: at(14, 30).Format(time.RFC3339)},
+			Location: "https://acme.zoom.us/j/123456789",
+		},
+		{
+			Summary:     "Late call",
+			Start:       &calendar.EventDateTime{DateTime: at(24, 2).Format(time.RFC3339)},
+			End:         &calendar.EventDateTime{DateTime: at(24, 30).Format(time.RFC3339)},
+			HangoutLink: "https://meet.google.com/ddd-eeee-fff",
+		},
+	} {
+		if _, err := store.Insert("primary", e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		now     time.Time
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "The meeting in progress is joined",
+			now:  at(13, 30),
+			want: "https://meet.google.com/aaa-bbbb-ccc",
+		},
+		{
+			name: "The last meeting started is joined",
+			now:  at(14, 5),
+			want: "https://acme.zoom.us/j/123456789",
+		},
+		{
+			name: "The next meeting is joined within the window",
+			now:  at(12, 56),
+			want: "https://meet.google.com/aaa-bbbb-ccc",
+		},
+		{
+			name: "The next meeting is joined when the window crosses midnight",
+			now:  at(23, 58),
+			want: "https://meet.google.com/ddd-eeee-fff",
+		},
+		{
+			name:    "Meetings past the window and events without link are left out",
+			now:     at(11, 55),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := joinEvent(singleStore(store), primary, 5*time.Minute, tt.now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("joinEvent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got.MeetLink != tt.want {
+				t.Errorf("joinEvent() = %+v, want link %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJoinWindowFlag(t *testing.T) {
+	soon := time.Now().Add(3 * time.Minute).Truncate(time.Minute)
+	store := gcal.NewMemoryStore()
+	if _, err := store.Insert("primary", &calendar.Event{
+		Summary:     "Sync",
+		Start:       &calendar.EventDateTime{DateTime: soon.Format(time.RFC3339)},
+		End:         &calendar.EventDateTime{DateTime: soon.Add(30 * time.Minute).Format(time.RFC3339)},
+		HangoutLink: "https://meet.google.com/aaa-bbbb-ccc",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantCode   int
+		wantStdout string
+	}{
+		{
+			name:       "Without --window, the configured window is used",
+			args:       []string{"join", "--print"},
+			wantCode:   ExitOK,
+			wantStdout: "https://meet.google.com/aaa-bbbb-ccc\n",
+		},
+		{
+			name:     "With --window 0, only a meeting in progress is joined",
+			args:     []string{"join", "--print", "--window", "0"},
+			wantCode: ExitError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			env := &Env{
+				Stdout: &stdout,
+				Stderr: &stderr,
+				Config: &config.Config{Join: config.JoinConfig{Window: "10m"}},
+				stores: map[string]gcal.EventStore{config.DefaultProfileName: store},
+			}
+			if got := run(env, newRootCommand(), tt.args); got != tt.wantCode {
+				t.Errorf("run() = %v, want %v (stderr: %v)", got, tt.wantCode, stderr.String())
+			}
+			if got := stdout.String(); got != tt.wantStdout {
+				t.Errorf("run() stdout = %q, want %q", got, tt.wantStdout)
+			}
+		})
+	}
+}
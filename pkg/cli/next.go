@@ -0,0 +1,132 @@
+package cli
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jiyeol-lee/gcli/pkg/config"
+	"github.com/jiyeol-lee/gcli/pkg/util"
+)
+
+var (
+	// defaultNextCount is how many events next shows
+	defaultNextCount = 5
+	// defaultNextDays is how many days ahead next looks for events
+	defaultNextDays = 14
+)
+
+// nextEvents function returns the first n events starting after now until the
+// end of the day days ahead, all-day events included, optionally with the
+// events of the work tracker. Whole days are listed, so that the listing is
+// served from the cache whatever the time.
+func nextEvents(
+	stores storeResolver,
+	calendars []config.CalendarConfig,
+	n, days int,
+	includeWork bool,
+	now time.Time,
+) ([]Event, error) {
+	today := util.StartOfDay(now)
+	events, err := listEvents(stores, calendars, today, today.AddDate(0, 0, days+1), now, !includeWork)
+	if err != nil {
+		return nil, err
+	}
+
+	next := []Event{}
+	for _, evt := range events {
+		if len(next) == n {
+			break
+		}
+		if evt.start.After(now) {
+			next = append(next, evt)
+		}
+	}
+
+	return next, nil
+}
+
+// countdown function returns the time until an event, e.g. "in 45m",
+// "in 3h05m" or "in 2d 4h"
+func countdown(d time.Duration) string {
+	if d < 24*time.Hour {
+		return "in " + util.FormatDuration(d)
+	}
+
+	days := int(d.Hours()) / 24
+	hours := int(d.Hours()) % 24
+
+	return fmt.Sprintf("in %dd %dh", days, hours)
+}
+
+func newNextCommand() *Command {
+	cmd := &Command{
+		Name:  "next",
+		Short: "List the upcoming events with their meeting links",
+		Long: "List the next events, across days, with their start, the time until then, their\n" +
+			"location and their video meeting link.",
+		Flags: flag.NewFlagSet("next", flag.ContinueOnError),
+	}
+	n := cmd.Flags.Int("n", defaultNextCount, "show `N` events")
+	days := cmd.Flags.Int("days", defaultNextDays, "look for events up to `N` days ahead")
+	includeWork := cmd.Flags.Bool("work", false, "include the work sessions and totals of the work tracker")
+
+	cmd.Run = func(env *Env, args []string) error {
+		if err := noArgs(cmd, args); err != nil {
+			return err
+		}
+		if *n < 1 {
+			return &usageError{cmd: cmd, msg: "-n must be positive"}
+		}
+		if *days < 1 {
+			return &usageError{cmd: cmd, msg: "--days must be positive"}
+		}
+
+		now := time.Now()
+		calendars := env.Calendars()
+		events, err := nextEvents(env.Store, calendars, *n, *days, *includeWork, now)
+		if err != nil {
+			return err
+		}
+
+		return env.render(events, func(w io.Writer) error {
+			// Events without location or link would leave trailing padding
+			var buf bytes.Buffer
+			tw := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+			for _, evt := range events {
+				when := evt.start.Local().Format("Mon 01-02 15:04")
+				if evt.AllDay {
+					when = evt.start.Format("Mon 01-02") + " all day"
+				}
+				fmt.Fprintf(
+					tw,
+					"%v\t%v\t%v%v\t%v\t%v\n",
+					when,
+					countdown(evt.start.Sub(now)),
+					calendarPrefix(calendars, evt),
+					evt.Summary,
+					evt.Location,
+					evt.MeetLink,
+				)
+			}
+
+			if err := tw.Flush(); err != nil {
+				return err
+			}
+			for _, line := range strings.SplitAfter(buf.String(), "\n") {
+				if line == "" {
+					continue
+				}
+				fmt.Fprintln(w, strings.TrimRight(line, " \n"))
+			}
+
+			return nil
+		})
+	}
+
+	return cmd
+}
@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+func TestNextEvents(t *testing.T) {
+	day := time.Date(2025, 3, 12, 0, 0, 0, 0, time.Local)
+	store := newTestStore(t, day)
+	tomorrow := day.AddDate(0, 0, 1)
+	for _, e := range []*calendar.Event{
+		{
+			Summary: "Sync",
+			Start:   &calendar.EventDateTime{DateTime: tomorrow.Add(10 * time.Hour).Format(time.RFC3339)},
+			End:     &calendar.EventDateTime{DateTime: tomorrow.Add(11 * time.Hour).Format(time.RFC3339)},
+			ConferenceData: &calendar.ConferenceData{EntryPoints: []*calendar.EntryPoint{
+				{EntryPointType: "phone", Uri: "tel:+1-555-0100"},
+				{EntryPointType: "video", Uri: "https://meet.google.com/abc-defg-hij"},
+			}},
+		},
+		{Summary: "Offsite", Start: &calendar.EventDateTime{Date: "2025-03-14"}, End: &calendar.EventDateTime{Date: "2025-03-15"}},
+	} {
+		if _, err := store.Insert("primary", e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name        string
+		now         time.Time
+		n           int
+		includeWork bool
+		want        []string
+	}{
+		{
+			name: "Events are listed across days, all-day ones included",
+			now:  day.Add(10 * time.Hour),
+			n:    5,
+			want: []string{"Lunch", "Sync", "Offsite"},
+		},
+		{
+			name: "At most n events are listed",
+			now:  day.Add(8 * time.Hour),
+			n:    2,
+			want: []string{"Standup", "Lunch"},
+		},
+		{
+			name:        "Work sessions are listed on demand",
+			now:         day.Add(8 * time.Hour),
+			n:           2,
+			includeWork: true,
+			want:        []string{"Working", "Standup"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := nextEvents(singleStore(store), primary, tt.n, defaultNextDays, tt.includeWork, tt.now)
+			if err != nil {
+				t.Fatalf("nextEvents() error = %v", err)
+			}
+
+			var summaries []string
+			for _, evt := range got {
+				summaries = append(summaries, evt.Summary)
+				if evt.Summary == "Sync" && evt.MeetLink != "https://meet.google.com/abc-defg-hij" {
+					t.Errorf("nextEvents() meet link = %q, want the video entry point", evt.MeetLink)
+				}
+			}
+			if !slices.Equal(summaries, tt.want) {
+				t.Errorf("nextEvents() = %q, want %q", summaries, tt.want)
+			}
+		})
+	}
+}
+
+func TestCountdown(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{d: 45 * time.Minute, want: "in 45m"},
+		{d: 3*time.Hour + 5*time.Minute, want: "in 3h05m"},
+		{d: 52*time.Hour + 30*time.Minute, want: "in 2d 4h"},
+	}
+	for _, tt := range tests {
+		if got := countdown(tt.d); got != tt.want {
+			t.Errorf("countdown(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
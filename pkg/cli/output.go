@@ -0,0 +1,146 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/jiyeol-lee/gcli/pkg/config"
+	"github.com/jiyeol-lee/gcli/pkg/gcal"
+	"google.golang.org/api/calendar/v3"
+)
+
+// Output formats accepted by --output
+const (
+	OutputText   = "text"
+	OutputJSON   = "json"
+	OutputNDJSON = "ndjson"
+)
+
+var outputFormats = []string{OutputText, OutputJSON, OutputNDJSON}
+
+// Event is the machine-readable form of a calendar event. Commands printing
+// events emit an array of it with --output json, or one object per line with
+// --output ndjson. Nothing to print is an empty array, never "N/A".
+//
+//	id             event id
+//	summary        event title
+//	start, end     RFC3339 time, or YYYY-MM-DD for all-day events
+//	all_day        true for all-day events
+//	minutes_until  minutes from now until the start, rounded, negative once started
+//	location       free-form location, empty if unset
+//	meet_link      video meeting link, empty if unset
+//	status         confirmed, tentative or cancelled
+//	link           link to the event in Google Calendar
+//	calendar       id of the calendar of the event
+//	calendar_name  configured label of the calendar, or its id
+type Event struct {
+	Id           string `json:"id"`
+	Summary      string `json:"summary"`
+	Start        string `json:"start"`
+	End          string `json:"end"`
+	AllDay       bool   `json:"all_day"`
+	MinutesUntil int    `json:"minutes_until"`
+	Location     string `json:"location"`
+	MeetLink     string `json:"meet_link"`
+	Status       string `json:"status"`
+	Link         string `json:"link"`
+	Calendar     string `json:"calendar"`
+	CalendarName string `json:"calendar_name"`
+
+	start time.Time
+	end   time.Time
+}
+
+// newEvent function converts an API event into its output form
+func newEvent(item *calendar.Event, now time.Time) (Event, error) {
+	st, err := gcal.ParseEventTime(item.Start)
+	if err != nil {
+		return Event{}, fmt.Errorf("unable to parse start time: %w", err)
+	}
+	et, err := gcal.ParseEventTime(item.End)
+	if err != nil {
+		return Event{}, fmt.Errorf("unable to parse end time: %w", err)
+	}
+
+	evt := Event{
+		Id:           item.Id,
+		Summary:      item.Summary,
+		AllDay:       gcal.IsAllDayEvent(item),
+		MinutesUntil: int(math.Round(st.Sub(now).Minutes())),
+		Location:     item.Location,
+		MeetLink:     gcal.GetMeetLink(item),
+		Status:       item.Status,
+		Link:         item.HtmlLink,
+		start:        st,
+		end:          et,
+	}
+	if evt.AllDay {
+		evt.Start = item.Start.Date
+		evt.End = item.End.Date
+	} else {
+		evt.Start = item.Start.DateTime
+		evt.End = item.End.DateTime
+	}
+
+	return evt, nil
+}
+
+// addGlobalFlags function registers the flags accepted by every command
+func addGlobalFlags(fs *flag.FlagSet, env *Env) {
+	fs.StringVar(&env.Output, "output", env.Output, "output format: text, json or ndjson")
+	fs.Var(&env.CalendarNames, "calendar", "`id` or configured label of a calendar to use, repeatable (default the configured calendars, or primary)")
+	fs.Var(&env.Profiles, "profile", "auth `profile` to use, repeatable (default the configured default profile)")
+	fs.BoolVar(&env.Offline, "offline", env.Offline, "serve events only from the local cache")
+	fs.DurationVar(&env.CacheTTL, "cache-ttl", env.CacheTTL, "serve cached events younger than this without calling the API")
+}
+
+func validateOutput(cmd *Command, env *Env) error {
+	if !slices.Contains(outputFormats, env.Output) {
+		return &usageError{cmd: cmd, msg: fmt.Sprintf("unknown output format %q", env.Output)}
+	}
+
+	return nil
+}
+
+// validateProfiles function checks the names given to --profile, which are
+// used in the paths of the tokens and of the cache
+func validateProfiles(cmd *Command, env *Env) error {
+	for _, profile := range env.Profiles {
+		if err := config.ValidateProfileName(profile); err != nil {
+			return &usageError{cmd: cmd, msg: err.Error()}
+		}
+	}
+
+	return nil
+}
+
+// render method writes v as JSON or NDJSON, or calls text for the text format
+func (e *Env) render(v any, text func(w io.Writer) error) error {
+	switch e.Output {
+	case OutputJSON:
+		enc := json.NewEncoder(e.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+
+	case OutputNDJSON:
+		enc := json.NewEncoder(e.Stdout)
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Slice {
+			return enc.Encode(v)
+		}
+		for i := 0; i < rv.Len(); i++ {
+			if err := enc.Encode(rv.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return text(e.Stdout)
+}
@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+func TestEnvRender(t *testing.T) {
+	type args struct {
+		output string
+		v      any
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "When output is text, text function is used",
+			args: args{
+				output: OutputText,
+				v:      []Event{},
+			},
+			want: "N/A",
+		},
+		{
+			name: "When output is json and nothing is found, print an empty array",
+			args: args{
+				output: OutputJSON,
+				v:      []Event{},
+			},
+			want: "[]\n",
+		},
+		{
+			name: "When output is ndjson, print one object per line",
+			args: args{
+				output: OutputNDJSON,
+				v:      []Event{{Id: "a", Summary: "A"}, {Id: "b", Summary: "B"}},
+			},
+			want: `{"id":"a","summary":"A","start":"","end":"","all_day":false,"minutes_until":0,"location":"","meet_link":"","status":"","link":"","calendar":"","calendar_name":""}` + "\n" +
+				`{"id":"b","summary":"B","start":"","end":"","all_day":false,"minutes_until":0,"location":"","meet_link":"","status":"","link":"","calendar":"","calendar_name":""}` + "\n",
+		},
+		{
+			name: "When output is ndjson and nothing is found, print nothing",
+			args: args{
+				output: OutputNDJSON,
+				v:      []Event{},
+			},
+			want: "",
+		},
+		{
+			name: "When output is ndjson and value is an object, print it on one line",
+			args: args{
+				output: OutputNDJSON,
+				v:      WorkStatus{Working: true, Since: "2025-01-01T09:00:00Z"},
+			},
+			want: `{"working":true,"since":"2025-01-01T09:00:00Z","session_minutes":0,"today_minutes":0,"paused":false,"paused_since":"","break_minutes":0,"project":"","task":"","stale":null,"target":null}` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			env := &Env{
+				Stdout: &stdout,
+				Output: tt.args.output,
+			}
+			err := env.render(tt.args.v, func(w io.Writer) error {
+				_, err := io.WriteString(w, "N/A")
+				return err
+			})
+			if err != nil {
+				t.Errorf("render() error = %v", err)
+				return
+			}
+			if got := stdout.String(); got != tt.want {
+				t.Errorf("render() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewEventMinutesUntil(t *testing.T) {
+	now := time.Date(2025, 3, 12, 9, 0, 0, 0, time.UTC)
+	item := &calendar.Event{
+		Start: &calendar.EventDateTime{DateTime: now.Add(4*time.Minute + 50*time.Second).Format(time.RFC3339)},
+		End:   &calendar.EventDateTime{DateTime: now.Add(time.Hour).Format(time.RFC3339)},
+	}
+
+	evt, err := newEvent(item, now)
+	if err != nil {
+		t.Fatalf("newEvent() error = %v", err)
+	}
+	if evt.MinutesUntil != 5 {
+		t.Errorf("newEvent() minutes until = %v, want 5, rounded as in the text output", evt.MinutesUntil)
+	}
+}
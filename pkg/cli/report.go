@@ -0,0 +1,487 @@
+package cli
+
+import (
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jiyeol-lee/gcli/pkg/gcal"
+	"github.com/jiyeol-lee/gcli/pkg/util"
+)
+
+// WorkReport is the machine-readable form of the timesheet printed by the
+// work report command.
+//
+//	from, to         first and last day of the report, as YYYY-MM-DD
+//	total_minutes    minutes worked over the report
+//	days_worked      days with at least one work session
+//	average_minutes  minutes worked per day worked
+//	days_missed      past weekdays without any work session
+//	days             every day of the report, see WorkDay
+//	weeks            every ISO week of the report, see WorkWeek
+//	projects         minutes by project and task, see WorkProject
+type WorkReport struct {
+	From           string        `json:"from"`
+	To             string        `json:"to"`
+	TotalMinutes   int           `json:"total_minutes"`
+	DaysWorked     int           `json:"days_worked"`
+	AverageMinutes int           `json:"average_minutes"`
+	DaysMissed     int           `json:"days_missed"`
+	Days           []WorkDay     `json:"days"`
+	Weeks          []WorkWeek    `json:"weeks"`
+	Projects       []WorkProject `json:"projects"`
+}
+
+// WorkDay is a day of a WorkReport.
+//
+//	date      YYYY-MM-DD
+//	weekday   Mon to Sun
+//	week      ISO week, as YYYY-Www
+//	sessions  work sessions started that day
+//	minutes   minutes worked that day, including the session in progress
+//	missed    true for a past weekday without any work session
+type WorkDay struct {
+	Date     string `json:"date"`
+	Weekday  string `json:"weekday"`
+	Week     string `json:"week"`
+	Sessions int    `json:"sessions"`
+	Minutes  int    `json:"minutes"`
+	Missed   bool   `json:"missed"`
+}
+
+// WorkWeek is an ISO week of a WorkReport, limited to the days of the report.
+//
+//	week             ISO week, as YYYY-Www
+//	minutes          minutes worked that week
+//	days_worked      days with at least one work session
+//	average_minutes  minutes worked per day worked
+//	days_missed      past weekdays without any work session
+type WorkWeek struct {
+	Week           string `json:"week"`
+	Minutes        int    `json:"minutes"`
+	DaysWorked     int    `json:"days_worked"`
+	AverageMinutes int    `json:"average_minutes"`
+	DaysMissed     int    `json:"days_missed"`
+}
+
+// WorkProject is a project of a WorkReport, sessions without project being
+// grouped under an empty one.
+//
+//	project  project given to work start --project
+//	minutes  minutes worked on the project
+//	tasks    minutes by task of the project, see WorkTask
+type WorkProject struct {
+	Project string     `json:"project"`
+	Minutes int        `json:"minutes"`
+	Tasks   []WorkTask `json:"tasks"`
+}
+
+// WorkTask is a task of a WorkProject, sessions without task being grouped
+// under an empty one.
+//
+//	task     task given to work start --task
+//	minutes  minutes worked on the task
+type WorkTask struct {
+	Task    string `json:"task"`
+	Minutes int    `json:"minutes"`
+}
+
+// Groupings of the work report given to --by
+const (
+	reportByDay     = "day"
+	reportByProject = "project"
+)
+
+// reportFlags holds the flags selecting the days of the work report
+type reportFlags struct {
+	from  string
+	to    string
+	week  bool
+	month bool
+}
+
+// resolve method returns the [from, to) days selected by the flags, the
+// current week by default
+func (r *reportFlags) resolve(now time.Time) (time.Time, time.Time, error) {
+	selected := 0
+	for _, set := range []bool{r.from != "" || r.to != "", r.week, r.month} {
+		if set {
+			selected++
+		}
+	}
+	if selected > 1 {
+		return time.Time{}, time.Time{}, fmt.Errorf("--from/--to, --week and --month are mutually exclusive")
+	}
+
+	switch {
+	case r.month:
+		first := util.StartOfDay(now).AddDate(0, 0, 1-now.Day())
+		return first, first.AddDate(0, 1, 0), nil
+
+	case r.from == "" && r.to == "":
+		monday := util.StartOfWeek(now)
+		return monday, monday.AddDate(0, 0, 7), nil
+	}
+
+	if r.from == "" || r.to == "" {
+		return time.Time{}, time.Time{}, fmt.Errorf("--from and --to go together")
+	}
+
+	from, err := time.ParseInLocation(dateLayout, r.from, time.Local)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid --from: %q is not YYYY-MM-DD", r.from)
+	}
+	to, err := time.ParseInLocation(dateLayout, r.to, time.Local)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid --to: %q is not YYYY-MM-DD", r.to)
+	}
+	to = to.AddDate(0, 0, 1)
+
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, fmt.Errorf("--from must not be after --to")
+	}
+
+	return from, to, nil
+}
+
+// isoWeek function returns the ISO week of the day, as YYYY-Www
+func isoWeek(t time.Time) string {
+	year, week := t.ISOWeek()
+	return fmt.Sprintf("%04d-W%02d", year, week)
+}
+
+// averageMinutes function returns the minutes per day worked, 0 without any
+func averageMinutes(minutes, days int) int {
+	if days == 0 {
+		return 0
+	}
+
+	return int(math.Round(float64(minutes) / float64(days)))
+}
+
+// workReport function collects the work sessions of the [from, to) days into
+// a timesheet. Sessions count on the day they start; the session in progress
+// counts until now, its breaks left out, and a stale one until its last
+// activity.
+func workReport(store gcal.EventStore, calendarId string, from, to, now time.Time) (WorkReport, error) {
+	c := newCalendar(store, calendarId, now)
+
+	evts, err := c.GetEvents(from, to)
+	if err != nil {
+		return WorkReport{}, fmt.Errorf("unable to retrieve work sessions: %w", err)
+	}
+
+	minutes := map[string]float64{}
+	sessions := map[string]int{}
+	tasks := map[workTags]float64{}
+	for _, item := range evts.Items {
+		value := c.GetWorkingHoursProperty(item)
+		if value == "" || item.Start == nil || item.Start.DateTime == "" {
+			continue
+		}
+
+		st, err := time.Parse(time.RFC3339, item.Start.DateTime)
+		if err != nil {
+			return WorkReport{}, fmt.Errorf("unable to parse start time: %w", err)
+		}
+		// Sessions overlapping the range but starting outside it count on other days
+		if st.Before(from) || !st.Before(to) {
+			continue
+		}
+
+		hours, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return WorkReport{}, fmt.Errorf("unable to parse working hours: %w", err)
+		}
+
+		var worked float64
+		if value == "0.000" {
+			// A stale session counts until its last activity, not until now
+			until := now
+			if c.IsStale(item) {
+				if until, err = c.GetLastActivity(item); err != nil {
+					return WorkReport{}, fmt.Errorf("unable to parse breaks: %w", err)
+				}
+			}
+			elapsed, err := c.GetWorkedDuration(item, st, until)
+			if err != nil {
+				return WorkReport{}, fmt.Errorf("unable to parse breaks: %w", err)
+			}
+			worked = elapsed.Minutes()
+		} else {
+			worked = hours * 60
+		}
+
+		day := st.Local().Format(dateLayout)
+		minutes[day] += worked
+		sessions[day]++
+		tasks[workTags{project: c.GetWorkProject(item), task: c.GetWorkTask(item)}] += worked
+	}
+
+	today := util.StartOfDay(now)
+	report := WorkReport{
+		From:  from.Format(dateLayout),
+		To:    to.AddDate(0, 0, -1).Format(dateLayout),
+		Days:  []WorkDay{},
+		Weeks: []WorkWeek{},
+	}
+	for d := from; d.Before(to); d = d.AddDate(0, 0, 1) {
+		date := d.Format(dateLayout)
+		day := WorkDay{
+			Date:     date,
+			Weekday:  d.Format("Mon"),
+			Week:     isoWeek(d),
+			Sessions: sessions[date],
+			Minutes:  int(math.Round(minutes[date])),
+		}
+		weekday := d.Weekday() != time.Saturday && d.Weekday() != time.Sunday
+		day.Missed = weekday && day.Sessions == 0 && d.Before(today)
+		report.Days = append(report.Days, day)
+
+		if len(report.Weeks) == 0 || report.Weeks[len(report.Weeks)-1].Week != day.Week {
+			report.Weeks = append(report.Weeks, WorkWeek{Week: day.Week})
+		}
+		week := &report.Weeks[len(report.Weeks)-1]
+		week.Minutes += day.Minutes
+		report.TotalMinutes += day.Minutes
+		if day.Sessions > 0 {
+			week.DaysWorked++
+			report.DaysWorked++
+		}
+		if day.Missed {
+			week.DaysMissed++
+			report.DaysMissed++
+		}
+	}
+
+	for i := range report.Weeks {
+		report.Weeks[i].AverageMinutes = averageMinutes(report.Weeks[i].Minutes, report.Weeks[i].DaysWorked)
+	}
+	report.AverageMinutes = averageMinutes(report.TotalMinutes, report.DaysWorked)
+	report.Projects = groupProjects(tasks)
+
+	return report, nil
+}
+
+// groupProjects function returns the minutes worked by project and task,
+// sorted by name, the sessions without project or task coming last
+func groupProjects(tasks map[workTags]float64) []WorkProject {
+	byName := func(a, b string) int {
+		switch {
+		case a == b:
+			return 0
+		case a == "":
+			return 1
+		case b == "":
+			return -1
+		}
+		return strings.Compare(a, b)
+	}
+
+	keys := slices.Collect(maps.Keys(tasks))
+	slices.SortFunc(keys, func(a, b workTags) int {
+		if cmp := byName(a.project, b.project); cmp != 0 {
+			return cmp
+		}
+		return byName(a.task, b.task)
+	})
+
+	projects := []WorkProject{}
+	for _, key := range keys {
+		if len(projects) == 0 || projects[len(projects)-1].Project != key.project {
+			projects = append(projects, WorkProject{Project: key.project, Tasks: []WorkTask{}})
+		}
+
+		project := &projects[len(projects)-1]
+		minutes := int(math.Round(tasks[key]))
+		project.Tasks = append(project.Tasks, WorkTask{Task: key.task, Minutes: minutes})
+		project.Minutes += minutes
+	}
+
+	return projects
+}
+
+// writeTable method writes the report as a table, a line per day with the
+// weekly subtotals, then the totals
+func (r WorkReport) writeTable(w io.Writer) error {
+	duration := func(minutes int) string {
+		return util.FormatDuration(time.Duration(minutes) * time.Minute)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "DATE\tDAY\tSESSIONS\tWORKED")
+	for _, week := range r.Weeks {
+		for _, day := range r.Days {
+			if day.Week != week.Week {
+				continue
+			}
+
+			worked := duration(day.Minutes)
+			switch {
+			case day.Missed:
+				worked = "missed"
+			case day.Sessions == 0:
+				worked = "-"
+			}
+			fmt.Fprintf(tw, "%v\t%v\t%v\t%v\n", day.Date, day.Weekday, day.Sessions, worked)
+		}
+		fmt.Fprintf(
+			tw,
+			"%v\t\t%v days\t%v\tavg %v\n",
+			week.Week,
+			week.DaysWorked,
+			duration(week.Minutes),
+			duration(week.AverageMinutes),
+		)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(
+		w,
+		"\nTotal %v over %v days (avg %v), %v days missed\n",
+		duration(r.TotalMinutes),
+		r.DaysWorked,
+		duration(r.AverageMinutes),
+		r.DaysMissed,
+	)
+
+	return nil
+}
+
+// writeProjectTable method writes the minutes of the report by project and
+// task as a table, then the total
+func (r WorkReport) writeProjectTable(w io.Writer) error {
+	duration := func(minutes int) string {
+		return util.FormatDuration(time.Duration(minutes) * time.Minute)
+	}
+	orNone := func(name string) string {
+		if name == "" {
+			return "(none)"
+		}
+		return name
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PROJECT\tTASK\tWORKED")
+	for _, project := range r.Projects {
+		fmt.Fprintf(tw, "%v\t\t%v\n", orNone(project.Project), duration(project.Minutes))
+		for _, task := range project.Tasks {
+			fmt.Fprintf(tw, "\t%v\t%v\n", orNone(task.Task), duration(task.Minutes))
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "\nTotal %v from %v to %v\n", duration(r.TotalMinutes), r.From, r.To)
+
+	return nil
+}
+
+// writeProjectCSV method writes the minutes of the report by project and task as CSV
+func (r WorkReport) writeProjectCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"project", "task", "minutes", "hours"})
+	for _, project := range r.Projects {
+		for _, task := range project.Tasks {
+			cw.Write([]string{
+				project.Project,
+				task.Task,
+				strconv.Itoa(task.Minutes),
+				strconv.FormatFloat(float64(task.Minutes)/60, 'f', 2, 64),
+			})
+		}
+	}
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// writeCSV method writes the days of the report as CSV
+func (r WorkReport) writeCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"date", "weekday", "week", "sessions", "minutes", "hours", "missed"})
+	for _, day := range r.Days {
+		cw.Write([]string{
+			day.Date,
+			day.Weekday,
+			day.Week,
+			strconv.Itoa(day.Sessions),
+			strconv.Itoa(day.Minutes),
+			strconv.FormatFloat(float64(day.Minutes)/60, 'f', 2, 64),
+			strconv.FormatBool(day.Missed),
+		})
+	}
+	cw.Flush()
+
+	return cw.Error()
+}
+
+func newWorkReportCommand() *Command {
+	cmd := &Command{
+		Name:  "report",
+		Short: "Show the timesheet of a week, a month or a date range",
+		Long: "Show the timesheet of the work sessions of the current week, the current month or\n" +
+			"a date range, by day and ISO week, with the totals, the averages per day worked and\n" +
+			"the weekdays missed. With --by project, the time is broken down by project and task\n" +
+			"instead, see work start --project and --task.",
+		Flags: flag.NewFlagSet("report", flag.ContinueOnError),
+	}
+	dates := &reportFlags{}
+	cmd.Flags.StringVar(&dates.from, "from", "", "first day of the report, as YYYY-MM-DD")
+	cmd.Flags.StringVar(&dates.to, "to", "", "last day of the report, as YYYY-MM-DD")
+	cmd.Flags.BoolVar(&dates.week, "week", false, "report the current week, Monday to Sunday (default)")
+	cmd.Flags.BoolVar(&dates.month, "month", false, "report the current month")
+	asCSV := cmd.Flags.Bool("csv", false, "print the days as CSV instead of a table")
+	by := cmd.Flags.String("by", reportByDay, "group the time by day or by project")
+
+	cmd.Run = func(env *Env, args []string) error {
+		if err := noArgs(cmd, args); err != nil {
+			return err
+		}
+
+		if *by != reportByDay && *by != reportByProject {
+			return &usageError{cmd: cmd, msg: fmt.Sprintf("unknown grouping %q, want day or project", *by)}
+		}
+
+		now := time.Now()
+		from, to, err := dates.resolve(now)
+		if err != nil {
+			return &usageError{cmd: cmd, msg: err.Error()}
+		}
+
+		store, calendarId, err := env.writeStore()
+		if err != nil {
+			return err
+		}
+
+		report, err := workReport(store, calendarId, from, to, now)
+		if err != nil {
+			return err
+		}
+
+		return env.render(report, func(w io.Writer) error {
+			switch {
+			case *by == reportByProject && *asCSV:
+				return report.writeProjectCSV(w)
+			case *by == reportByProject:
+				return report.writeProjectTable(w)
+			case *asCSV:
+				return report.writeCSV(w)
+			}
+			return report.writeTable(w)
+		})
+	}
+
+	return cmd
+}
@@ -0,0 +1,186 @@
+package cli
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jiyeol-lee/gcli/pkg/gcal"
+	"google.golang.org/api/calendar/v3"
+)
+
+func TestWorkReport(t *testing.T) {
+	store := gcal.NewMemoryStore()
+	// Wednesday
+	day := time.Date(2025, 3, 12, 0, 0, 0, 0, time.Local)
+	session := func(d, h, minutes int) {
+		t.Helper()
+		start := day.AddDate(0, 0, d).Add(time.Duration(h) * time.Hour)
+		if _, err := startWork(store, "primary", workTags{}, start); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := stopWork(store, "primary", start.Add(time.Duration(minutes)*time.Minute)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	session(-2, 9, 240) // Monday
+	session(-2, 14, 180)
+	session(0, 9, 360) // Wednesday
+	if _, err := startWork(store, "primary", workTags{}, day.Add(13*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	now := day.Add(14*time.Hour + 30*time.Minute)
+	from, to, err := (&reportFlags{}).resolve(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := workReport(store, "primary", from, to, now)
+	if err != nil {
+		t.Fatalf("workReport() error = %v", err)
+	}
+
+	if report.From != "2025-03-10" || report.To != "2025-03-16" || len(report.Days) != 7 {
+		t.Fatalf("workReport() = %v to %v with %v days, want the week of 2025-03-10", report.From, report.To, len(report.Days))
+	}
+	wantMinutes := []int{420, 0, 450, 0, 0, 0, 0}
+	for i, want := range wantMinutes {
+		if got := report.Days[i].Minutes; got != want {
+			t.Errorf("day %v minutes = %v, want %v", report.Days[i].Date, got, want)
+		}
+	}
+	if report.Days[2].Sessions != 2 {
+		t.Errorf("Wednesday sessions = %v, want 2", report.Days[2].Sessions)
+	}
+	if !report.Days[1].Missed || report.Days[3].Missed {
+		t.Errorf("missed days = %+v, want only Tuesday", report.Days)
+	}
+	if report.TotalMinutes != 870 || report.DaysWorked != 2 || report.AverageMinutes != 435 || report.DaysMissed != 1 {
+		t.Errorf("workReport() totals = %+v", report)
+	}
+	if len(report.Weeks) != 1 || report.Weeks[0].Week != "2025-W11" || report.Weeks[0].Minutes != 870 {
+		t.Errorf("workReport() weeks = %+v, want 2025-W11", report.Weeks)
+	}
+
+	var csv strings.Builder
+	if err := report.writeCSV(&csv); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(csv.String()), "\n"); len(lines) != 8 || lines[1] != "2025-03-10,Mon,2025-W11,2,420,7.00,false" {
+		t.Errorf("writeCSV() = %v", csv.String())
+	}
+}
+
+func TestWorkReportStaleSession(t *testing.T) {
+	store := gcal.NewMemoryStore()
+	// Wednesday
+	day := time.Date(2025, 3, 12, 0, 0, 0, 0, time.Local)
+	monday := day.AddDate(0, 0, -2)
+
+	// Forgotten on Monday, paused at 11:00 and never stopped
+	if _, err := startWork(store, "primary", workTags{}, monday.Add(9*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pauseWork(store, "primary", monday.Add(11*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := workReport(store, "primary", monday, day.AddDate(0, 0, 1), day.Add(14*time.Hour))
+	if err != nil {
+		t.Fatalf("workReport() error = %v", err)
+	}
+	if report.Days[0].Minutes != 120 || report.TotalMinutes != 120 {
+		t.Errorf("workReport() = %+v, want the stale session counted until its last activity", report)
+	}
+}
+
+func TestReportFlagsResolve(t *testing.T) {
+	now := time.Date(2025, 3, 12, 10, 0, 0, 0, time.Local)
+	date := func(m time.Month, d int) time.Time {
+		return time.Date(2025, m, d, 0, 0, 0, 0, time.Local)
+	}
+
+	tests := []struct {
+		name     string
+		flags    reportFlags
+		wantFrom time.Time
+		wantTo   time.Time
+		wantErr  bool
+	}{
+		{name: "The current week by default", wantFrom: date(3, 10), wantTo: date(3, 17)},
+		{name: "The current month", flags: reportFlags{month: true}, wantFrom: date(3, 1), wantTo: date(4, 1)},
+		{name: "An inclusive date range", flags: reportFlags{from: "2025-02-01", to: "2025-02-28"}, wantFrom: date(2, 1), wantTo: date(3, 1)},
+		{name: "--from without --to", flags: reportFlags{from: "2025-02-01"}, wantErr: true},
+		{name: "--week and --month", flags: reportFlags{week: true, month: true}, wantErr: true},
+		{name: "A reversed range", flags: reportFlags{from: "2025-02-02", to: "2025-02-01"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, err := tt.flags.resolve(now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolve() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !from.Equal(tt.wantFrom) || !to.Equal(tt.wantTo) {
+				t.Errorf("resolve() = %v, %v, want %v, %v", from, to, tt.wantFrom, tt.wantTo)
+			}
+		})
+	}
+}
+
+func TestWorkReportByProject(t *testing.T) {
+	store := gcal.NewMemoryStore()
+	day := time.Date(2025, 3, 12, 0, 0, 0, 0, time.Local)
+	session := func(tags workTags, h, minutes int) {
+		t.Helper()
+		start := day.Add(time.Duration(h) * time.Hour)
+		if _, err := startWork(store, "primary", tags, start); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := stopWork(store, "primary", start.Add(time.Duration(minutes)*time.Minute)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	session(workTags{project: "acme", task: "login"}, 8, 60)
+	session(workTags{}, 10, 30)
+	session(workTags{project: "acme"}, 11, 45)
+	session(workTags{project: "acme", task: "login"}, 13, 90)
+	session(workTags{project: "beta", task: "docs"}, 15, 15)
+	// Overlapping the day but counting on the day before, as left by earlier versions
+	if _, err := store.Insert("primary", &calendar.Event{
+		Summary: "Work (2.000 hrs)",
+		Start:   &calendar.EventDateTime{DateTime: day.Add(-time.Hour).Format(time.RFC3339)},
+		End:     &calendar.EventDateTime{DateTime: day.Add(time.Hour).Format(time.RFC3339)},
+		ExtendedProperties: &calendar.EventExtendedProperties{
+			Private: map[string]string{"WORKING_HOURS": "2.000"},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := workReport(store, "primary", day, day.AddDate(0, 0, 1), day.Add(20*time.Hour))
+	if err != nil {
+		t.Fatalf("workReport() error = %v", err)
+	}
+	if report.TotalMinutes != 240 {
+		t.Errorf("workReport() total = %v, want 240", report.TotalMinutes)
+	}
+
+	want := []WorkProject{
+		{Project: "acme", Minutes: 195, Tasks: []WorkTask{{Task: "login", Minutes: 150}, {Task: "", Minutes: 45}}},
+		{Project: "beta", Minutes: 15, Tasks: []WorkTask{{Task: "docs", Minutes: 15}}},
+		{Project: "", Minutes: 30, Tasks: []WorkTask{{Task: "", Minutes: 30}}},
+	}
+	if !reflect.DeepEqual(report.Projects, want) {
+		t.Errorf("workReport() projects = %+v, want %+v", report.Projects, want)
+	}
+
+	events, err := listEvents(singleStore(store), primary, day, day.AddDate(0, 0, 1), day.Add(20*time.Hour), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if events[2].Summary != "Work (1.000 hrs) - acme: login" {
+		t.Errorf("session summary = %q, want it tagged", events[1].Summary)
+	}
+}
@@ -0,0 +1,294 @@
+package cli
+
+import (
+	"cmp"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jiyeol-lee/gcli/pkg/config"
+	"github.com/jiyeol-lee/gcli/pkg/gcal"
+	"github.com/jiyeol-lee/gcli/pkg/util"
+)
+
+var (
+	// candidateStep is the interval between the starts of the candidate slots
+	candidateStep = 30 * time.Minute
+	// comfortMargin is the margin past which slots rank by start only
+	comfortMargin = time.Hour
+	// defaultScheduleCount is how many candidate slots schedule shows
+	defaultScheduleCount = 5
+)
+
+// ScheduleSlot is the machine-readable form of a candidate slot printed by
+// the schedule command, best first.
+//
+//	start, end      RFC3339 bounds of the meeting
+//	margin_minutes  minutes from the meeting to the closest edge of the working
+//	                hours or meeting of any participant, capped at 60
+type ScheduleSlot struct {
+	Start         string `json:"start"`
+	End           string `json:"end"`
+	MarginMinutes int    `json:"margin_minutes"`
+}
+
+// participant is someone attending the meeting being scheduled
+type participant struct {
+	name  string
+	loc   *time.Location
+	hours workHours
+	busy  []gcal.Period
+}
+
+// windows method returns the working hours of the participant within the
+// [from, to) range
+func (p participant) windows(from, to time.Time) []gcal.Period {
+	return p.hours.windows(from, to, p.loc)
+}
+
+// margin method returns the time between the slot and the closest edge of the
+// working hours or meeting of the participant
+func (p participant) margin(slot gcal.Period, windows []gcal.Period) time.Duration {
+	var before, after time.Time
+	for _, w := range windows {
+		if !w.Start.After(slot.Start) && !w.End.Before(slot.End) {
+			before, after = w.Start, w.End
+			break
+		}
+	}
+
+	for _, b := range p.busy {
+		if !b.End.After(slot.Start) && b.End.After(before) {
+			before = b.End
+		}
+		if !b.Start.Before(slot.End) && b.Start.Before(after) {
+			after = b.Start
+		}
+	}
+
+	return min(slot.Start.Sub(before), after.Sub(slot.End))
+}
+
+// intersectPeriods function returns the periods common to both sorted and
+// merged lists of periods
+func intersectPeriods(a, b []gcal.Period) []gcal.Period {
+	common := []gcal.Period{}
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		start, end := util.MaxTime(a[i].Start, b[j].Start), util.MinTime(a[i].End, b[j].End)
+		if start.Before(end) {
+			common = append(common, gcal.Period{Start: start, End: end})
+		}
+
+		if a[i].End.Before(b[j].End) {
+			i++
+		} else {
+			j++
+		}
+	}
+
+	return common
+}
+
+// rankSlots function returns at most n meetings of duration at which every
+// participant is free within their working hours, from now on. The slots
+// with the widest margin, up to comfortMargin, rank first, then the earliest.
+func rankSlots(participants []participant, from, to, now time.Time, duration time.Duration, n int) []ScheduleSlot {
+	if len(participants) == 0 {
+		return []ScheduleSlot{}
+	}
+
+	windows := make([][]gcal.Period, len(participants))
+	var common []gcal.Period
+	for i, p := range participants {
+		windows[i] = p.windows(from, to)
+		free := freeSlots(windows[i], p.busy, now, duration, 0)
+		if i == 0 {
+			common = free
+			continue
+		}
+		common = intersectPeriods(common, free)
+	}
+
+	type candidate struct {
+		slot   gcal.Period
+		margin time.Duration
+	}
+	var candidates []candidate
+	for _, period := range common {
+		// Meetings start on the hour or half hour of the day of the first
+		// participant, unless the period starts otherwise
+		for start := period.Start; !start.Add(duration).After(period.End); {
+			slot := gcal.Period{Start: start, End: start.Add(duration)}
+
+			margin := comfortMargin
+			for i, p := range participants {
+				margin = min(margin, p.margin(slot, windows[i]))
+			}
+			candidates = append(candidates, candidate{slot: slot, margin: margin})
+
+			day := util.StartOfDay(start.In(participants[0].loc))
+			start = day.Add(start.Sub(day).Truncate(candidateStep) + candidateStep)
+		}
+	}
+
+	slices.SortStableFunc(candidates, func(a, b candidate) int {
+		if c := cmp.Compare(b.margin, a.margin); c != 0 {
+			return c
+		}
+		return a.slot.Start.Compare(b.slot.Start)
+	})
+
+	slots := []ScheduleSlot{}
+	for _, c := range candidates[:min(n, len(candidates))] {
+		slots = append(slots, ScheduleSlot{
+			Start:         c.slot.Start.Format(time.RFC3339),
+			End:           c.slot.End.Format(time.RFC3339),
+			MarginMinutes: int(c.margin.Minutes()),
+		})
+	}
+
+	return slots
+}
+
+// attendees function returns the attendees as participants, their busy times
+// being queried with the store and their time zones and working hours read
+// from the configuration
+func attendees(cfg *config.Config, store gcal.EventStore, emails []string, from, to time.Time) ([]participant, error) {
+	lister, ok := store.(gcal.BusyLister)
+	if !ok {
+		return nil, fmt.Errorf("the store cannot query the busy times of the attendees")
+	}
+
+	busy, err := lister.FreeBusy(emails, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve the busy times of the attendees: %w", err)
+	}
+
+	var people []participant
+	for _, email := range emails {
+		loc, start, end, err := cfg.GetPerson(email)
+		if err != nil {
+			return nil, err
+		}
+
+		people = append(people, participant{
+			name: email,
+			loc:  loc,
+			// Monday to Friday
+			hours: workHours{start: start, end: end, days: [7]bool{false, true, true, true, true, true, false}},
+			busy:  gcal.MergePeriods(busy[email]),
+		})
+	}
+
+	return people, nil
+}
+
+func newScheduleCommand() *Command {
+	cmd := &Command{
+		Name:  "schedule",
+		Short: "Find the slots at which everyone is free for a meeting",
+		Long: "Find the slots of --duration at which you and the people given to --with are all\n" +
+			"free, within everyone's working hours, and optionally create the meeting. The\n" +
+			"time zones and working hours of the people are configured under \"people\", the\n" +
+			"local time zone and your working hours being used otherwise. Slots away from\n" +
+			"the edges of the working hours and from other meetings rank first.",
+		Flags: flag.NewFlagSet("schedule", flag.ContinueOnError),
+	}
+	var with stringsFlag
+	cmd.Flags.Var(&with, "with", "`emails` of the people to meet, comma-separated or repeated")
+	duration := cmd.Flags.String("duration", "30m", "`duration` of the meeting")
+	within := cmd.Flags.String("within", "5 days", "search these `days`, e.g. tomorrow, \"next week\" or \"3 days\"")
+	n := cmd.Flags.Int("n", defaultScheduleCount, "show `N` slots")
+	create := cmd.Flags.String("create", "", "create the meeting with this `summary` and invite the people")
+	pick := cmd.Flags.Int("pick", 1, "create the meeting at the slot ranked `N`")
+
+	cmd.Run = func(env *Env, args []string) error {
+		if err := noArgs(cmd, args); err != nil {
+			return err
+		}
+		if len(with) == 0 {
+			return &usageError{cmd: cmd, msg: "--with is required"}
+		}
+		if *n < 1 || *pick < 1 {
+			return &usageError{cmd: cmd, msg: "-n and --pick must be positive"}
+		}
+
+		now := time.Now()
+		d, err := util.ParseHumanDuration(*duration)
+		if err != nil || d <= 0 {
+			return &usageError{cmd: cmd, msg: fmt.Sprintf("invalid --duration %q", *duration)}
+		}
+		from, to, err := util.ParseHumanRange(*within, now)
+		if err != nil {
+			return &usageError{cmd: cmd, msg: fmt.Sprintf("invalid --within: %v", err)}
+		}
+
+		hours, err := workHoursOf(env.Config, false)
+		if err != nil {
+			return err
+		}
+		busy, err := calendarsBusy(env.Store, env.Calendars(), from, to, now)
+		if err != nil {
+			return err
+		}
+		store, calendarId, err := env.writeStore()
+		if err != nil {
+			return err
+		}
+		people, err := attendees(env.Config, store, with, from, to)
+		if err != nil {
+			return err
+		}
+
+		me := participant{name: "me", loc: time.Local, hours: hours, busy: busy}
+		slots := rankSlots(append([]participant{me}, people...), from, to, now, d, max(*n, *pick))
+
+		if *create == "" {
+			slots = slots[:min(*n, len(slots))]
+			return env.render(slots, func(w io.Writer) error {
+				if len(slots) == 0 {
+					fmt.Fprintln(w, "No slot at which everyone is free")
+					return nil
+				}
+				for i, slot := range slots {
+					fmt.Fprintf(w, "%v. %v-%v\n", i+1, localDateTime(slot.Start), localClock(slot.End))
+				}
+				return nil
+			})
+		}
+
+		if *pick > len(slots) {
+			return fmt.Errorf("no slot ranked %v, %v found", *pick, len(slots))
+		}
+		slot := slots[*pick-1]
+		start, _ := time.Parse(time.RFC3339, slot.Start)
+		end, _ := time.Parse(time.RFC3339, slot.End)
+
+		evt, err := addEvent(store, calendarId, gcal.EventInput{
+			Summary:   *create,
+			Start:     start,
+			End:       end,
+			Attendees: with,
+		}, now)
+		if err != nil {
+			return err
+		}
+
+		return env.render(evt, func(w io.Writer) error {
+			fmt.Fprintf(
+				w,
+				"Created event %v on %v with %v\n%v\n",
+				evt.Id,
+				localDateTime(evt.Start),
+				strings.Join(with, ", "),
+				evt.Link,
+			)
+			return nil
+		})
+	}
+
+	return cmd
+}
@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/jiyeol-lee/gcli/pkg/config"
+	"github.com/jiyeol-lee/gcli/pkg/gcal"
+	"google.golang.org/api/calendar/v3"
+)
+
+func TestIntersectPeriods(t *testing.T) {
+	day := time.Date(2025, 3, 12, 0, 0, 0, 0, time.Local)
+	at := func(h int) time.Time {
+		return day.Add(time.Duration(h) * time.Hour)
+	}
+
+	a := []gcal.Period{{Start: at(9), End: at(12)}, {Start: at(13), End: at(18)}}
+	b := []gcal.Period{{Start: at(8), End: at(10)}, {Start: at(11), End: at(14)}, {Start: at(16), End: at(20)}}
+	want := []gcal.Period{
+		{Start: at(9), End: at(10)},
+		{Start: at(11), End: at(12)},
+		{Start: at(13), End: at(14)},
+		{Start: at(16), End: at(18)},
+	}
+
+	if got := intersectPeriods(a, b); !periodsEqual(got, want) {
+		t.Errorf("intersectPeriods() = %v, want %v", got, want)
+	}
+}
+
+func TestRankSlots(t *testing.T) {
+	day := time.Date(2025, 3, 12, 0, 0, 0, 0, time.UTC)
+	at := func(h, m int) time.Time {
+		return day.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute)
+	}
+	store := gcal.NewMemoryStore()
+	for id, events := range map[string][]*calendar.Event{
+		"a@example.com": {
+			{Summary: "Standup", Start: &calendar.EventDateTime{DateTime: at(13, 0).Format(time.RFC3339)}, End: &calendar.EventDateTime{DateTime: at(14, 0).Format(time.RFC3339)}},
+			{Summary: "Focus", Transparency: "transparent", Start: &calendar.EventDateTime{DateTime: at(14, 0).Format(time.RFC3339)}, End: &calendar.EventDateTime{DateTime: at(16, 0).Format(time.RFC3339)}},
+		},
+		"b@example.com": {
+			{Summary: "Review", Start: &calendar.EventDateTime{DateTime: at(16, 0).Format(time.RFC3339)}, End: &calendar.EventDateTime{DateTime: at(16, 30).Format(time.RFC3339)}},
+		},
+	} {
+		for _, e := range events {
+			if _, err := store.Insert(id, e); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	from, to := day, day.AddDate(0, 0, 1)
+	cfg := &config.Config{People: map[string]config.PersonConfig{
+		"a@example.com": {TimeZone: "UTC"},
+		"b@example.com": {TimeZone: "UTC", Hours: "12:00-21:00"},
+	}}
+	people, err := attendees(cfg, store, []string{"a@example.com", "b@example.com"}, from, to)
+	if err != nil {
+		t.Fatalf("attendees() error = %v", err)
+	}
+	me := participant{
+		name:  "me",
+		loc:   time.UTC,
+		hours: workHours{start: 9 * time.Hour, end: 18 * time.Hour, days: [7]bool{false, true, true, true, true, true, false}},
+		busy:  []gcal.Period{{Start: at(17, 0), End: at(18, 0)}},
+	}
+
+	// Everyone is free 12:00-13:00, 14:00-16:00 and 16:30-17:00
+	got := rankSlots(append([]participant{me}, people...), from, to, at(7, 0), 30*time.Minute, 3)
+	want := []ScheduleSlot{
+		{Start: at(14, 30).Format(time.RFC3339), End: at(15, 0).Format(time.RFC3339), MarginMinutes: 30},
+		{Start: at(15, 0).Format(time.RFC3339), End: at(15, 30).Format(time.RFC3339), MarginMinutes: 30},
+		{Start: at(12, 0).Format(time.RFC3339), End: at(12, 30).Format(time.RFC3339), MarginMinutes: 0},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("rankSlots() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("rankSlots()[%v] = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestRankSlotsHalfHourZone(t *testing.T) {
+	// Kathmandu is 5h45m ahead of UTC, its half hours are not those of UTC
+	kathmandu, err := time.LoadLocation("Asia/Kathmandu")
+	if err != nil {
+		t.Skip(err)
+	}
+	day := time.Date(2025, 3, 12, 0, 0, 0, 0, kathmandu)
+	at := func(h, m int) time.Time {
+		return day.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute)
+	}
+	me := participant{
+		name:  "me",
+		loc:   kathmandu,
+		hours: workHours{start: 9 * time.Hour, end: 18 * time.Hour, days: [7]bool{false, true, true, true, true, true, false}},
+		busy:  []gcal.Period{{Start: at(8, 0), End: at(9, 10)}, {Start: at(10, 30), End: at(18, 0)}},
+	}
+
+	// Free 09:10-10:30
+	got := rankSlots([]participant{me}, day, day.AddDate(0, 0, 1), at(7, 0), 30*time.Minute, 5)
+	var starts []string
+	for _, slot := range got {
+		starts = append(starts, slot.Start)
+	}
+	slices.Sort(starts)
+	want := []string{at(9, 10).Format(time.RFC3339), at(9, 30).Format(time.RFC3339), at(10, 0).Format(time.RFC3339)}
+
+	if !slices.Equal(starts, want) {
+		t.Errorf("rankSlots() starts = %v, want %v", starts, want)
+	}
+}
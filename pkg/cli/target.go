@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/jiyeol-lee/gcli/pkg/gcal"
+	"github.com/jiyeol-lee/gcli/pkg/util"
+)
+
+// WorkTarget is the machine-readable form of the progress towards the work
+// targets, part of the work status.
+//
+//	target_minutes          minutes to work today
+//	remaining_minutes       minutes left to reach today's target, 0 once reached
+//	finish_at               RFC3339 time today's target is reached at if the session
+//	                        in progress goes on, empty when not working or reached
+//	week_minutes            minutes worked this week, Monday to now
+//	week_target_minutes     minutes to work this week
+//	week_remaining_minutes  minutes left to reach the weekly target, 0 once reached
+//	balance_minutes         overtime of the week, the minutes worked on the previous
+//	                        days beyond or below their targets, plus the minutes worked
+//	                        today beyond its target
+type WorkTarget struct {
+	TargetMinutes        int    `json:"target_minutes"`
+	RemainingMinutes     int    `json:"remaining_minutes"`
+	FinishAt             string `json:"finish_at"`
+	WeekMinutes          int    `json:"week_minutes"`
+	WeekTargetMinutes    int    `json:"week_target_minutes"`
+	WeekRemainingMinutes int    `json:"week_remaining_minutes"`
+	BalanceMinutes       int    `json:"balance_minutes"`
+}
+
+// workTargets are the times to work by weekday, indexed by time.Weekday, and
+// over a week
+type workTargets struct {
+	daily  [7]time.Duration
+	weekly time.Duration
+}
+
+// targets method returns the work targets of the configuration
+func (e *Env) targets() (workTargets, error) {
+	daily, weekly, err := e.Config.GetWorkTargets()
+	if err != nil {
+		return workTargets{}, err
+	}
+
+	return workTargets{daily: daily, weekly: weekly}, nil
+}
+
+// workTarget function returns the progress of the status towards the targets,
+// the time worked this week being read from the calendar
+func workTarget(store gcal.EventStore, calendarId string, targets workTargets, status WorkStatus, now time.Time) (WorkTarget, error) {
+	monday := util.StartOfWeek(now)
+	today := util.StartOfDay(now)
+
+	report, err := workReport(store, calendarId, monday, today.AddDate(0, 0, 1), now)
+	if err != nil {
+		return WorkTarget{}, err
+	}
+
+	// The previous days come from the report, today from the status, which
+	// counts the share of today of a session started on an earlier day
+	var pastTarget time.Duration
+	pastMinutes := 0
+	for i, d := 0, monday; d.Before(today); i, d = i+1, d.AddDate(0, 0, 1) {
+		pastTarget += targets.daily[d.Weekday()]
+		pastMinutes += report.Days[i].Minutes
+	}
+	weekMinutes := pastMinutes + status.TodayMinutes
+
+	todayTarget := int(targets.daily[now.Weekday()].Minutes())
+	target := WorkTarget{
+		TargetMinutes:        todayTarget,
+		RemainingMinutes:     max(todayTarget-status.TodayMinutes, 0),
+		WeekMinutes:          weekMinutes,
+		WeekTargetMinutes:    int(targets.weekly.Minutes()),
+		WeekRemainingMinutes: max(int(targets.weekly.Minutes())-weekMinutes, 0),
+		BalanceMinutes:       pastMinutes - int(pastTarget.Minutes()) + max(status.TodayMinutes-todayTarget, 0),
+	}
+	if status.Working && !status.Paused && target.RemainingMinutes > 0 {
+		target.FinishAt = now.Add(time.Duration(target.RemainingMinutes) * time.Minute).Format(time.RFC3339)
+	}
+
+	return target, nil
+}
+
+// summary method returns the progress as a short line, e.g.
+// "5h12m/8h00m, 2h48m left, done at 17:30, week +1h30m"
+func (t WorkTarget) summary(todayMinutes int) string {
+	duration := func(minutes int) string {
+		return util.FormatDuration(time.Duration(minutes) * time.Minute)
+	}
+
+	parts := []string{fmt.Sprintf("%v/%v", duration(todayMinutes), duration(t.TargetMinutes))}
+	if t.RemainingMinutes > 0 {
+		parts = append(parts, duration(t.RemainingMinutes)+" left")
+	}
+	if t.FinishAt != "" {
+		parts = append(parts, "done at "+localClock(t.FinishAt))
+	}
+
+	balance := duration(t.BalanceMinutes)
+	if t.BalanceMinutes >= 0 {
+		balance = "+" + balance
+	}
+	parts = append(parts, "week "+balance)
+
+	return strings.Join(parts, ", ")
+}
+
+func newWorkTargetCommand() *Command {
+	cmd := &Command{
+		Name:  "target",
+		Short: "Show the time worked against the daily and weekly targets",
+		Long: "Show the time worked today against the daily target, the time left and when it is\n" +
+			"reached if the session in progress goes on, and the overtime balance of the week, on\n" +
+			"a single line for status bars. The targets are set in the \"work\" section of the\n" +
+			"configuration, 8h Monday to Friday by default.",
+		Flags: flag.NewFlagSet("target", flag.ContinueOnError),
+	}
+
+	cmd.Run = func(env *Env, args []string) error {
+		if err := noArgs(cmd, args); err != nil {
+			return err
+		}
+
+		targets, err := env.targets()
+		if err != nil {
+			return err
+		}
+
+		store, calendarId, err := env.writeStore()
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		status, err := getWorkStatus(store, calendarId, now)
+		if err != nil {
+			return err
+		}
+		target, err := workTarget(store, calendarId, targets, status, now)
+		if err != nil {
+			return err
+		}
+
+		return env.render(target, func(w io.Writer) error {
+			fmt.Fprintln(w, target.summary(status.TodayMinutes))
+			return nil
+		})
+	}
+
+	return cmd
+}
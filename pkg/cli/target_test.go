@@ -0,0 +1,133 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jiyeol-lee/gcli/pkg/gcal"
+)
+
+func TestWorkTarget(t *testing.T) {
+	store := gcal.NewMemoryStore()
+	monday := time.Date(2025, 3, 10, 0, 0, 0, 0, time.Local)
+	at := func(day, h, m int) time.Time {
+		return monday.AddDate(0, 0, day).Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute)
+	}
+	targets := workTargets{
+		daily:  [7]time.Duration{0, 8 * time.Hour, 8 * time.Hour, 8 * time.Hour, 8 * time.Hour, 8 * time.Hour, 0},
+		weekly: 40 * time.Hour,
+	}
+
+	// 9h on Monday, 6h on Tuesday
+	for _, session := range [][2]time.Time{{at(0, 9, 0), at(0, 18, 0)}, {at(1, 9, 0), at(1, 15, 0)}} {
+		if _, err := startWork(store, "primary", workTags{}, session[0]); err != nil {
+			t.Fatalf("startWork() error = %v", err)
+		}
+		if _, err := stopWork(store, "primary", session[1]); err != nil {
+			t.Fatalf("stopWork() error = %v", err)
+		}
+	}
+
+	if _, err := startWork(store, "primary", workTags{}, at(2, 9, 0)); err != nil {
+		t.Fatalf("startWork() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want WorkTarget
+	}{
+		{
+			name: "While working, the finish time is projected",
+			now:  at(2, 12, 0),
+			want: WorkTarget{
+				TargetMinutes:        480,
+				RemainingMinutes:     300,
+				FinishAt:             at(2, 17, 0).Format(time.RFC3339),
+				WeekMinutes:          1080,
+				WeekTargetMinutes:    2400,
+				WeekRemainingMinutes: 1320,
+				BalanceMinutes:       -60,
+			},
+		},
+		{
+			name: "Past the target, today's overtime adds to the balance",
+			now:  at(2, 18, 0),
+			want: WorkTarget{
+				TargetMinutes:        480,
+				WeekMinutes:          1440,
+				WeekTargetMinutes:    2400,
+				WeekRemainingMinutes: 960,
+				BalanceMinutes:       0,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, err := getWorkStatus(store, "primary", tt.now)
+			if err != nil {
+				t.Fatalf("getWorkStatus() error = %v", err)
+			}
+
+			got, err := workTarget(store, "primary", targets, status, tt.now)
+			if err != nil {
+				t.Fatalf("workTarget() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("workTarget() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorkTargetSummary(t *testing.T) {
+	target := WorkTarget{
+		TargetMinutes:    480,
+		RemainingMinutes: 150,
+		FinishAt:         time.Date(2025, 3, 12, 17, 30, 0, 0, time.Local).Format(time.RFC3339),
+		BalanceMinutes:   90,
+	}
+
+	want := "5h30m/8h00m, 2h30m left, done at 17:30, week +1h30m"
+	if got := target.summary(330); got != want {
+		t.Errorf("summary() = %q, want %q", got, want)
+	}
+
+	target = WorkTarget{TargetMinutes: 480, BalanceMinutes: -45}
+	want = "8h10m/8h00m, week -45m"
+	if got := target.summary(490); got != want {
+		t.Errorf("summary() = %q, want %q", got, want)
+	}
+}
+
+func TestWorkTargetSessionFromLastWeek(t *testing.T) {
+	store := gcal.NewMemoryStore()
+	monday := time.Date(2025, 3, 10, 0, 0, 0, 0, time.Local)
+	targets := workTargets{
+		daily:  [7]time.Duration{0, 8 * time.Hour, 8 * time.Hour, 8 * time.Hour, 8 * time.Hour, 8 * time.Hour, 0},
+		weekly: 40 * time.Hour,
+	}
+
+	// Open since Sunday 22:00, the session counts 10h on Monday only
+	if _, err := startWork(store, "primary", workTags{}, monday.Add(-2*time.Hour)); err != nil {
+		t.Fatalf("startWork() error = %v", err)
+	}
+	now := monday.Add(10 * time.Hour)
+	status := WorkStatus{Working: true, TodayMinutes: 600}
+
+	got, err := workTarget(store, "primary", targets, status, now)
+	if err != nil {
+		t.Fatalf("workTarget() error = %v", err)
+	}
+	want := WorkTarget{
+		TargetMinutes:        480,
+		WeekMinutes:          600,
+		WeekTargetMinutes:    2400,
+		WeekRemainingMinutes: 1800,
+		BalanceMinutes:       120,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("workTarget() = %+v, want %+v", got, want)
+	}
+}
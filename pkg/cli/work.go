@@ -0,0 +1,719 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/jiyeol-lee/gcli/pkg/gcal"
+	"github.com/jiyeol-lee/gcli/pkg/util"
+	"google.golang.org/api/calendar/v3"
+)
+
+// WorkStatus is the machine-readable form of the work tracker state printed
+// by the work commands.
+//
+//	working          true while a session is in progress
+//	since            RFC3339 start of the session in progress, empty otherwise
+//	session_minutes  minutes of the session in progress, or of the one just stopped
+//	today_minutes    minutes worked today, including the session in progress
+//	paused           true while the session in progress is on a break
+//	paused_since     RFC3339 start of the break in progress, empty otherwise
+//	break_minutes    minutes of breaks of the session, left out of session_minutes
+//	project, task    tags of the session in progress, empty if untagged
+//	stale            RFC3339 starts of the sessions left open on previous days,
+//	                 to be closed with work recover
+//	target           progress towards the work targets, see WorkTarget, set by
+//	                 work status only
+type WorkStatus struct {
+	Working        bool        `json:"working"`
+	Since          string      `json:"since"`
+	SessionMinutes int         `json:"session_minutes"`
+	TodayMinutes   int         `json:"today_minutes"`
+	Paused         bool        `json:"paused"`
+	PausedSince    string      `json:"paused_since"`
+	BreakMinutes   int         `json:"break_minutes"`
+	Project        string      `json:"project"`
+	Task           string      `json:"task"`
+	Stale          []string    `json:"stale"`
+	Target         *WorkTarget `json:"target"`
+}
+
+// workTags are the project and task a work session is tagged with
+type workTags struct {
+	project string
+	task    string
+}
+
+// findSessions function returns the work session in progress, if any, and
+// the pending sessions left open on previous days
+func findSessions(c *gcal.Calendar) (*calendar.Event, []*calendar.Event, error) {
+	pending, err := c.GetPendingEvents()
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to retrieve pending events: %w", err)
+	}
+
+	var current *calendar.Event
+	var stale []*calendar.Event
+	for _, evt := range pending {
+		if c.IsStale(evt) {
+			stale = append(stale, evt)
+			continue
+		}
+		current = evt
+	}
+
+	return current, stale, nil
+}
+
+// staleError function returns the error of the commands refusing to run
+// while a forgotten work session is open
+func staleError(stale []*calendar.Event) error {
+	return fmt.Errorf(
+		"the work session started %v was never stopped, close it with 'gcli work recover'",
+		localDateTime(stale[0].Start.DateTime),
+	)
+}
+
+// openSession function returns the work session in progress, refusing when
+// there is none or a forgotten one is open
+func openSession(c *gcal.Calendar) (*calendar.Event, error) {
+	current, stale, err := findSessions(c)
+	if err != nil {
+		return nil, err
+	}
+	if len(stale) > 0 {
+		return nil, staleError(stale)
+	}
+	if current == nil {
+		return nil, fmt.Errorf("no work session in progress")
+	}
+
+	return current, nil
+}
+
+// startWork function opens a work session tagged with tags, refusing when one
+// is already open
+func startWork(store gcal.EventStore, calendarId string, tags workTags, now time.Time) (WorkStatus, error) {
+	c := newCalendar(store, calendarId, now)
+
+	current, stale, err := findSessions(c)
+	if err != nil {
+		return WorkStatus{}, err
+	}
+	if len(stale) > 0 {
+		return WorkStatus{}, staleError(stale)
+	}
+	if current != nil {
+		return WorkStatus{}, fmt.Errorf("a work session is already in progress since %v", current.Start.DateTime)
+	}
+
+	evt, err := c.AddPendingEvent(tags.project, tags.task)
+	if err != nil {
+		return WorkStatus{}, fmt.Errorf("unable to start work session: %w", err)
+	}
+
+	return WorkStatus{
+		Working: true,
+		Since:   evt.Start.DateTime,
+		Project: tags.project,
+		Task:    tags.task,
+		Stale:   []string{},
+	}, nil
+}
+
+// stopWork function closes the open work session, split at midnight when
+// started on a previous day, and recomputes the daily totals
+func stopWork(store gcal.EventStore, calendarId string, now time.Time) (WorkStatus, error) {
+	c := newCalendar(store, calendarId, now)
+
+	current, err := openSession(c)
+	if err != nil {
+		return WorkStatus{}, err
+	}
+
+	sessions, err := c.ClosePendingEvent(current, now)
+	if err != nil {
+		return WorkStatus{}, fmt.Errorf("unable to stop work session: %w", err)
+	}
+
+	var session float64
+	for _, evt := range sessions {
+		hours, err := strconv.ParseFloat(c.GetWorkingHoursProperty(evt), 64)
+		if err != nil {
+			return WorkStatus{}, fmt.Errorf("unable to parse working hours: %w", err)
+		}
+		session += hours
+	}
+
+	evts, err := c.GetTodayEvents(true)
+	if err != nil {
+		return WorkStatus{}, fmt.Errorf("unable to retrieve today's events: %w", err)
+	}
+
+	return WorkStatus{
+		Working:        false,
+		SessionMinutes: int(session * 60),
+		TodayMinutes:   int(c.GetTodayWorkingHours(evts) * 60),
+		Stale:          []string{},
+	}, nil
+}
+
+// getWorkStatus function returns the open work session and today's total,
+// along with the forgotten sessions
+func getWorkStatus(store gcal.EventStore, calendarId string, now time.Time) (WorkStatus, error) {
+	c := newCalendar(store, calendarId, now)
+
+	evts, err := c.GetTodayEvents(true)
+	if err != nil {
+		return WorkStatus{}, fmt.Errorf("unable to retrieve today's events: %w", err)
+	}
+
+	current, stale, err := findSessions(c)
+	if err != nil {
+		return WorkStatus{}, err
+	}
+
+	total := time.Duration(c.GetTodayWorkingHours(evts) * float64(time.Hour))
+
+	status := WorkStatus{
+		TodayMinutes: int(total.Minutes()),
+	}
+	if current != nil {
+		status, err = sessionStatus(c, current, total, now)
+		if err != nil {
+			return WorkStatus{}, err
+		}
+	}
+
+	status.Stale = []string{}
+	for _, evt := range stale {
+		status.Stale = append(status.Stale, evt.Start.DateTime)
+	}
+
+	return status, nil
+}
+
+// sessionStatus function returns the status of the session in progress, whose
+// breaks are left out of the time worked
+func sessionStatus(c *gcal.Calendar, pendingEvent *calendar.Event, total time.Duration, now time.Time) (WorkStatus, error) {
+	st, err := gcal.ParseEventTime(pendingEvent.Start)
+	if err != nil {
+		return WorkStatus{}, fmt.Errorf("unable to parse start time: %w", err)
+	}
+
+	elapsed, err := c.GetWorkedDuration(pendingEvent, st, now)
+	if err != nil {
+		return WorkStatus{}, fmt.Errorf("unable to parse breaks: %w", err)
+	}
+	today := util.StartOfDay(now)
+	elapsedToday, err := c.GetWorkedDuration(pendingEvent, today, now)
+	if err != nil {
+		return WorkStatus{}, fmt.Errorf("unable to parse breaks: %w", err)
+	}
+	breaks, err := c.GetBreakDuration(pendingEvent)
+	if err != nil {
+		return WorkStatus{}, fmt.Errorf("unable to parse breaks: %w", err)
+	}
+
+	status := WorkStatus{
+		Working:        true,
+		Since:          pendingEvent.Start.DateTime,
+		SessionMinutes: int(elapsed.Minutes()),
+		TodayMinutes:   int((total + elapsedToday).Minutes()),
+		BreakMinutes:   int(breaks.Minutes()),
+		Project:        c.GetWorkProject(pendingEvent),
+		Task:           c.GetWorkTask(pendingEvent),
+		Stale:          []string{},
+	}
+
+	current, err := c.GetBreakInProgress(pendingEvent)
+	if err != nil {
+		return WorkStatus{}, fmt.Errorf("unable to parse breaks: %w", err)
+	}
+	if current != nil {
+		status.Paused = true
+		status.PausedSince = current.Start.Format(time.RFC3339)
+	}
+
+	return status, nil
+}
+
+// pauseWork function starts a break in the open work session
+func pauseWork(store gcal.EventStore, calendarId string, now time.Time) (WorkStatus, error) {
+	return changeBreak(store, calendarId, now, (*gcal.Calendar).PausePendingEvent)
+}
+
+// resumeWork function ends the break in progress of the open work session
+func resumeWork(store gcal.EventStore, calendarId string, now time.Time) (WorkStatus, error) {
+	return changeBreak(store, calendarId, now, (*gcal.Calendar).ResumePendingEvent)
+}
+
+// changeBreak function applies change to the open work session and returns
+// the resulting status
+func changeBreak(
+	store gcal.EventStore,
+	calendarId string,
+	now time.Time,
+	change func(*gcal.Calendar, *calendar.Event) (*calendar.Event, error),
+) (WorkStatus, error) {
+	c := newCalendar(store, calendarId, now)
+
+	current, err := openSession(c)
+	if err != nil {
+		return WorkStatus{}, err
+	}
+
+	evt, err := change(c, current)
+	if err != nil {
+		return WorkStatus{}, err
+	}
+
+	evts, err := c.GetTodayEvents(true)
+	if err != nil {
+		return WorkStatus{}, fmt.Errorf("unable to retrieve today's events: %w", err)
+	}
+	total := time.Duration(c.GetTodayWorkingHours(evts) * float64(time.Hour))
+
+	return sessionStatus(c, evt, total, now)
+}
+
+// Ways of closing a forgotten work session given to work recover
+const (
+	recoverAt           = "at"
+	recoverLastActivity = "last-activity"
+	recoverDiscard      = "discard"
+)
+
+// RecoveredSession is the machine-readable form of a forgotten work session
+// closed or discarded by the work recover command.
+//
+//	since      RFC3339 start of the session
+//	until      RFC3339 end the session was closed at, empty when discarded
+//	discarded  true when the session was deleted
+//	minutes    minutes worked, over every day the session was split into
+type RecoveredSession struct {
+	Since     string `json:"since"`
+	Until     string `json:"until"`
+	Discarded bool   `json:"discarded"`
+	Minutes   int    `json:"minutes"`
+}
+
+// recoverWork function closes the forgotten work sessions at the time given
+// by at, parsed relative to the start of each session, or at their last
+// known activity, or discards them
+func recoverWork(store gcal.EventStore, calendarId string, how string, at string, now time.Time) ([]RecoveredSession, error) {
+	c := newCalendar(store, calendarId, now)
+
+	_, stale, err := findSessions(c)
+	if err != nil {
+		return nil, err
+	}
+
+	recovered := []RecoveredSession{}
+	for _, evt := range stale {
+		session := RecoveredSession{Since: evt.Start.DateTime}
+
+		if how == recoverDiscard {
+			if err := c.DiscardPendingEvent(evt); err != nil {
+				return recovered, fmt.Errorf("unable to discard work session: %w", err)
+			}
+			session.Discarded = true
+			recovered = append(recovered, session)
+			continue
+		}
+
+		st, err := gcal.ParseEventTime(evt.Start)
+		if err != nil {
+			return recovered, fmt.Errorf("unable to parse start time: %w", err)
+		}
+
+		var end time.Time
+		if how == recoverAt {
+			end, err = util.ParseHumanTime(at, st.Local())
+			if err != nil {
+				return recovered, fmt.Errorf("invalid --at: %w", err)
+			}
+			if end.Before(st) || end.After(now) {
+				return recovered, fmt.Errorf(
+					"--at %v is not between the start of the session at %v and now",
+					at,
+					localDateTime(evt.Start.DateTime),
+				)
+			}
+		} else {
+			end, err = c.GetLastActivity(evt)
+			if err != nil {
+				return recovered, fmt.Errorf("unable to find last activity: %w", err)
+			}
+		}
+
+		sessions, err := c.ClosePendingEvent(evt, end)
+		if err != nil {
+			return recovered, fmt.Errorf("unable to close work session: %w", err)
+		}
+
+		var hours float64
+		for _, s := range sessions {
+			h, err := strconv.ParseFloat(c.GetWorkingHoursProperty(s), 64)
+			if err != nil {
+				return recovered, fmt.Errorf("unable to parse working hours: %w", err)
+			}
+			hours += h
+		}
+		session.Until = end.Format(time.RFC3339)
+		session.Minutes = int(hours * 60)
+		recovered = append(recovered, session)
+	}
+
+	return recovered, nil
+}
+
+// since method returns the start of the session in progress in local time
+func (s WorkStatus) since() string {
+	return localClock(s.Since)
+}
+
+// tags method returns the project and task of the session in progress, for
+// the text output, e.g. " on acme: fix login"
+func (s WorkStatus) tags() string {
+	switch {
+	case s.Project != "" && s.Task != "":
+		return fmt.Sprintf(" on %v: %v", s.Project, s.Task)
+	case s.Project != "":
+		return " on " + s.Project
+	case s.Task != "":
+		return " on " + s.Task
+	}
+
+	return ""
+}
+
+// localDateTime function returns the local date and time of day of an RFC3339 time
+func localDateTime(rfc3339 string) string {
+	t, err := time.Parse(time.RFC3339, rfc3339)
+	if err != nil {
+		return rfc3339
+	}
+
+	return t.Local().Format("Mon 2006-01-02 15:04")
+}
+
+// localClock function returns the local time of day of an RFC3339 time
+func localClock(rfc3339 string) string {
+	t, err := time.Parse(time.RFC3339, rfc3339)
+	if err != nil {
+		return rfc3339
+	}
+
+	return t.Local().Format("15:04")
+}
+
+func newWorkCommand() *Command {
+	return &Command{
+		Name:  "work",
+		Args:  "<command>",
+		Short: "Track work sessions",
+		Long: "Track work sessions as events on the calendar. Each session is a \"Working\" event\n" +
+			"and the hours of the day are summed up in a \"Total Work\" all-day event. Breaks taken\n" +
+			"with pause and resume are left out of the hours of the session.",
+		Flags: flag.NewFlagSet("work", flag.ContinueOnError),
+		Subcommands: []*Command{
+			newWorkStartCommand(),
+			newWorkStopCommand(),
+			newWorkPauseCommand(),
+			newWorkResumeCommand(),
+			newWorkStatusCommand(),
+			newWorkTargetCommand(),
+			newWorkRecoverCommand(),
+			newWorkReportCommand(),
+		},
+	}
+}
+
+func newWorkStartCommand() *Command {
+	cmd := &Command{
+		Name:  "start",
+		Short: "Start a work session",
+		Flags: flag.NewFlagSet("start", flag.ContinueOnError),
+	}
+	var tags workTags
+	cmd.Flags.StringVar(&tags.project, "project", "", "tag the session with the `project`")
+	cmd.Flags.StringVar(&tags.task, "task", "", "tag the session with the `task`")
+
+	cmd.Run = func(env *Env, args []string) error {
+		if err := noArgs(cmd, args); err != nil {
+			return err
+		}
+
+		store, calendarId, err := env.writeStore()
+		if err != nil {
+			return err
+		}
+
+		status, err := startWork(store, calendarId, tags, time.Now())
+		if err != nil {
+			return err
+		}
+
+		return env.render(status, func(w io.Writer) error {
+			fmt.Fprintf(w, "Work session%v started at %v\n", status.tags(), status.since())
+			return nil
+		})
+	}
+
+	return cmd
+}
+
+func newWorkStopCommand() *Command {
+	cmd := &Command{
+		Name:  "stop",
+		Short: "Stop the work session and update today's total",
+		Flags: flag.NewFlagSet("stop", flag.ContinueOnError),
+	}
+
+	cmd.Run = func(env *Env, args []string) error {
+		if err := noArgs(cmd, args); err != nil {
+			return err
+		}
+
+		store, calendarId, err := env.writeStore()
+		if err != nil {
+			return err
+		}
+
+		status, err := stopWork(store, calendarId, time.Now())
+		if err != nil {
+			return err
+		}
+
+		return env.render(status, func(w io.Writer) error {
+			fmt.Fprintf(
+				w,
+				"Work session stopped after %v (today: %v)\n",
+				util.FormatDuration(time.Duration(status.SessionMinutes)*time.Minute),
+				util.FormatDuration(time.Duration(status.TodayMinutes)*time.Minute),
+			)
+			return nil
+		})
+	}
+
+	return cmd
+}
+
+func newWorkStatusCommand() *Command {
+	cmd := &Command{
+		Name:  "status",
+		Short: "Show the elapsed time of the work session and today's total against the target",
+		Flags: flag.NewFlagSet("status", flag.ContinueOnError),
+	}
+
+	cmd.Run = func(env *Env, args []string) error {
+		if err := noArgs(cmd, args); err != nil {
+			return err
+		}
+
+		targets, err := env.targets()
+		if err != nil {
+			return err
+		}
+
+		store, calendarId, err := env.writeStore()
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		status, err := getWorkStatus(store, calendarId, now)
+		if err != nil {
+			return err
+		}
+		target, err := workTarget(store, calendarId, targets, status, now)
+		if err != nil {
+			return err
+		}
+		status.Target = &target
+
+		for _, since := range status.Stale {
+			fmt.Fprintf(
+				env.Stderr,
+				"gcli: warning: the work session started %v was never stopped, close it with 'gcli work recover'\n",
+				localDateTime(since),
+			)
+		}
+
+		return env.render(status, func(w io.Writer) error {
+			today := target.summary(status.TodayMinutes)
+			if !status.Working {
+				fmt.Fprintf(w, "Not working (today: %v)\n", today)
+				return nil
+			}
+
+			if status.Paused {
+				fmt.Fprintf(
+					w,
+					"Paused since %v after %v (today: %v)\n",
+					localClock(status.PausedSince),
+					util.FormatDuration(time.Duration(status.SessionMinutes)*time.Minute),
+					today,
+				)
+				return nil
+			}
+
+			fmt.Fprintf(
+				w,
+				"Working%v since %v for %v (today: %v)\n",
+				status.tags(),
+				status.since(),
+				util.FormatDuration(time.Duration(status.SessionMinutes)*time.Minute),
+				today,
+			)
+			return nil
+		})
+	}
+
+	return cmd
+}
+
+func newWorkPauseCommand() *Command {
+	cmd := &Command{
+		Name:  "pause",
+		Short: "Pause the work session for a break",
+		Flags: flag.NewFlagSet("pause", flag.ContinueOnError),
+	}
+
+	cmd.Run = func(env *Env, args []string) error {
+		if err := noArgs(cmd, args); err != nil {
+			return err
+		}
+
+		store, calendarId, err := env.writeStore()
+		if err != nil {
+			return err
+		}
+
+		status, err := pauseWork(store, calendarId, time.Now())
+		if err != nil {
+			return err
+		}
+
+		return env.render(status, func(w io.Writer) error {
+			fmt.Fprintf(
+				w,
+				"Work session paused at %v after %v\n",
+				localClock(status.PausedSince),
+				util.FormatDuration(time.Duration(status.SessionMinutes)*time.Minute),
+			)
+			return nil
+		})
+	}
+
+	return cmd
+}
+
+func newWorkResumeCommand() *Command {
+	cmd := &Command{
+		Name:  "resume",
+		Short: "Resume the paused work session",
+		Flags: flag.NewFlagSet("resume", flag.ContinueOnError),
+	}
+
+	cmd.Run = func(env *Env, args []string) error {
+		if err := noArgs(cmd, args); err != nil {
+			return err
+		}
+
+		store, calendarId, err := env.writeStore()
+		if err != nil {
+			return err
+		}
+
+		status, err := resumeWork(store, calendarId, time.Now())
+		if err != nil {
+			return err
+		}
+
+		return env.render(status, func(w io.Writer) error {
+			fmt.Fprintf(
+				w,
+				"Work session resumed after %v of breaks\n",
+				util.FormatDuration(time.Duration(status.BreakMinutes)*time.Minute),
+			)
+			return nil
+		})
+	}
+
+	return cmd
+}
+
+func newWorkRecoverCommand() *Command {
+	cmd := &Command{
+		Name:  "recover",
+		Short: "Close or discard the work sessions left open on previous days",
+		Long: "Close or discard the work sessions left open on previous days, having started\n" +
+			"before today's midnight, which the other work commands refuse to run with. A\n" +
+			"session is closed at the time given to --at, read relative to the day the session\n" +
+			"started, e.g. \"18:00\", or at its last known activity, its start or its last\n" +
+			"break. Sessions crossing midnight are split into a session per day.",
+		Flags: flag.NewFlagSet("recover", flag.ContinueOnError),
+	}
+	at := cmd.Flags.String("at", "", "close the sessions at `time`, e.g. 18:00 or \"tomorrow 01:30\"")
+	lastActivity := cmd.Flags.Bool("last-activity", false, "close the sessions at their last known activity")
+	discard := cmd.Flags.Bool("discard", false, "delete the sessions")
+
+	cmd.Run = func(env *Env, args []string) error {
+		if err := noArgs(cmd, args); err != nil {
+			return err
+		}
+
+		var how []string
+		if *at != "" {
+			how = append(how, recoverAt)
+		}
+		if *lastActivity {
+			how = append(how, recoverLastActivity)
+		}
+		if *discard {
+			how = append(how, recoverDiscard)
+		}
+		if len(how) != 1 {
+			return &usageError{cmd: cmd, msg: "exactly one of --at, --last-activity and --discard is required"}
+		}
+
+		store, calendarId, err := env.writeStore()
+		if err != nil {
+			return err
+		}
+
+		recovered, err := recoverWork(store, calendarId, how[0], *at, time.Now())
+		if err != nil {
+			return err
+		}
+
+		return env.render(recovered, func(w io.Writer) error {
+			if len(recovered) == 0 {
+				fmt.Fprintln(w, "No work session left open")
+				return nil
+			}
+
+			for _, session := range recovered {
+				if session.Discarded {
+					fmt.Fprintf(w, "Discarded the work session started %v\n", localDateTime(session.Since))
+					continue
+				}
+				fmt.Fprintf(
+					w,
+					"Closed the work session started %v at %v after %v\n",
+					localDateTime(session.Since),
+					localDateTime(session.Until),
+					util.FormatDuration(time.Duration(session.Minutes)*time.Minute),
+				)
+			}
+			return nil
+		})
+	}
+
+	return cmd
+}
@@ -0,0 +1,320 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jiyeol-lee/gcli/pkg/config"
+	"github.com/jiyeol-lee/gcli/pkg/gcal"
+)
+
+func TestWorkSession(t *testing.T) {
+	store := gcal.NewMemoryStore()
+	day := time.Date(2025, 3, 12, 0, 0, 0, 0, time.Local)
+	at := func(h, m int) time.Time {
+		return day.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute)
+	}
+
+	status, err := getWorkStatus(store, "primary", at(8, 0))
+	if err != nil {
+		t.Fatalf("getWorkStatus() error = %v", err)
+	}
+	if status.Working {
+		t.Errorf("getWorkStatus() = %+v, want not working", status)
+	}
+
+	if _, err := stopWork(store, "primary", at(8, 0)); err == nil {
+		t.Errorf("stopWork() without session, want error")
+	}
+
+	if _, err := startWork(store, "primary", workTags{}, at(9, 0)); err != nil {
+		t.Fatalf("startWork() error = %v", err)
+	}
+
+	if _, err := startWork(store, "primary", workTags{}, at(9, 30)); err == nil {
+		t.Errorf("startWork() with session in progress, want error")
+	}
+
+	status, err = getWorkStatus(store, "primary", at(10, 0))
+	if err != nil {
+		t.Fatalf("getWorkStatus() error = %v", err)
+	}
+	if !status.Working || status.SessionMinutes != 60 || status.TodayMinutes != 60 {
+		t.Errorf("getWorkStatus() = %+v, want working for 60 minutes", status)
+	}
+
+	status, err = stopWork(store, "primary", at(10, 30))
+	if err != nil {
+		t.Fatalf("stopWork() error = %v", err)
+	}
+	if status.Working || status.SessionMinutes != 90 || status.TodayMinutes != 90 {
+		t.Errorf("stopWork() = %+v, want stopped after 90 minutes", status)
+	}
+
+	if _, err := startWork(store, "primary", workTags{}, at(13, 0)); err != nil {
+		t.Fatalf("startWork() error = %v", err)
+	}
+
+	status, err = stopWork(store, "primary", at(14, 0))
+	if err != nil {
+		t.Fatalf("stopWork() error = %v", err)
+	}
+	if status.SessionMinutes != 60 || status.TodayMinutes != 150 {
+		t.Errorf("stopWork() = %+v, want 60 minutes, 150 today", status)
+	}
+
+	events, err := listEvents(singleStore(store), []config.CalendarConfig{{Id: "primary"}}, day, day.AddDate(0, 0, 1), at(15, 0), false)
+	if err != nil {
+		t.Fatalf("listEvents() error = %v", err)
+	}
+	totals := 0
+	for _, evt := range events {
+		if evt.Summary == "Total Work (2.500 hrs)" {
+			totals++
+		}
+	}
+	if totals != 1 {
+		t.Errorf("listEvents() = %+v, want a single Total Work (2.500 hrs) event", events)
+	}
+}
+
+func TestWorkBreaks(t *testing.T) {
+	store := gcal.NewMemoryStore()
+	day := time.Date(2025, 3, 12, 0, 0, 0, 0, time.Local)
+	at := func(h, m int) time.Time {
+		return day.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute)
+	}
+
+	if _, err := pauseWork(store, "primary", at(9, 0)); err == nil {
+		t.Errorf("pauseWork() without session, want error")
+	}
+
+	if _, err := startWork(store, "primary", workTags{}, at(9, 0)); err != nil {
+		t.Fatalf("startWork() error = %v", err)
+	}
+
+	if _, err := resumeWork(store, "primary", at(10, 0)); err == nil {
+		t.Errorf("resumeWork() while not paused, want error")
+	}
+
+	status, err := pauseWork(store, "primary", at(12, 0))
+	if err != nil {
+		t.Fatalf("pauseWork() error = %v", err)
+	}
+	if !status.Paused || status.SessionMinutes != 180 {
+		t.Errorf("pauseWork() = %+v, want paused after 180 minutes", status)
+	}
+
+	if _, err := pauseWork(store, "primary", at(12, 10)); err == nil {
+		t.Errorf("pauseWork() while paused, want error")
+	}
+
+	status, err = getWorkStatus(store, "primary", at(12, 30))
+	if err != nil {
+		t.Fatalf("getWorkStatus() error = %v", err)
+	}
+	if !status.Paused || status.SessionMinutes != 180 || status.BreakMinutes != 30 {
+		t.Errorf("getWorkStatus() = %+v, want paused after 180 minutes with a 30 minutes break", status)
+	}
+
+	status, err = resumeWork(store, "primary", at(13, 0))
+	if err != nil {
+		t.Fatalf("resumeWork() error = %v", err)
+	}
+	if status.Paused || status.BreakMinutes != 60 {
+		t.Errorf("resumeWork() = %+v, want resumed after a 60 minutes break", status)
+	}
+
+	// Stopping while paused ends the break
+	if _, err := pauseWork(store, "primary", at(15, 0)); err != nil {
+		t.Fatalf("pauseWork() error = %v", err)
+	}
+	status, err = stopWork(store, "primary", at(15, 30))
+	if err != nil {
+		t.Fatalf("stopWork() error = %v", err)
+	}
+	if status.SessionMinutes != 300 || status.TodayMinutes != 300 {
+		t.Errorf("stopWork() = %+v, want 300 minutes worked", status)
+	}
+
+	// A session spent paused is stopped, not left pending
+	if _, err := startWork(store, "primary", workTags{}, at(16, 0)); err != nil {
+		t.Fatalf("startWork() error = %v", err)
+	}
+	if _, err := pauseWork(store, "primary", at(16, 0)); err != nil {
+		t.Fatalf("pauseWork() error = %v", err)
+	}
+	if _, err := stopWork(store, "primary", at(16, 30)); err != nil {
+		t.Fatalf("stopWork() error = %v", err)
+	}
+	status, err = getWorkStatus(store, "primary", at(16, 45))
+	if err != nil {
+		t.Fatalf("getWorkStatus() error = %v", err)
+	}
+	if status.Working || status.TodayMinutes != 300 {
+		t.Errorf("getWorkStatus() = %+v, want stopped after 300 minutes worked", status)
+	}
+}
+
+func TestWorkAcrossMidnight(t *testing.T) {
+	store := gcal.NewMemoryStore()
+	day := time.Date(2025, 3, 12, 0, 0, 0, 0, time.Local)
+	at := func(h, m int) time.Time {
+		return day.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute)
+	}
+
+	if _, err := startWork(store, "primary", workTags{project: "acme"}, at(22, 0)); err != nil {
+		t.Fatalf("startWork() error = %v", err)
+	}
+
+	status, err := getWorkStatus(store, "primary", at(23, 59))
+	if err != nil {
+		t.Fatalf("getWorkStatus() error = %v", err)
+	}
+	if !status.Working || status.SessionMinutes != 119 {
+		t.Errorf("getWorkStatus() = %+v, want 119 minutes in progress", status)
+	}
+
+	// Past midnight, the session was left open on a previous day
+	status, err = getWorkStatus(store, "primary", at(25, 0))
+	if err != nil {
+		t.Fatalf("getWorkStatus() error = %v", err)
+	}
+	if status.Working || len(status.Stale) != 1 {
+		t.Errorf("getWorkStatus() = %+v, want a stale session", status)
+	}
+
+	recovered, err := recoverWork(store, "primary", recoverAt, "tomorrow 01:30", at(25, 30))
+	if err != nil {
+		t.Fatalf("recoverWork() error = %v", err)
+	}
+	if len(recovered) != 1 || recovered[0].Minutes != 210 {
+		t.Errorf("recoverWork() = %+v, want 210 minutes", recovered)
+	}
+
+	events, err := listEvents(singleStore(store), primary, day, day.AddDate(0, 0, 2), at(26, 0), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, evt := range events {
+		got = append(got, evt.Summary)
+	}
+	want := []string{
+		"Total Work (2.000 hrs)",
+		"Work (2.000 hrs) - acme",
+		"Total Work (1.500 hrs)",
+		"Work (1.500 hrs) - acme",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("listEvents() = %q, want %q", got, want)
+	}
+}
+
+func TestWorkStaleSession(t *testing.T) {
+	store := gcal.NewMemoryStore()
+	day := time.Date(2025, 3, 12, 0, 0, 0, 0, time.Local)
+
+	if _, err := startWork(store, "primary", workTags{}, day.Add(18*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		now       time.Time
+		wantStale bool
+	}{
+		{name: "A session started today is in progress", now: day.Add(23*time.Hour + 59*time.Minute)},
+		{name: "A session started yesterday is stale, however recent", now: day.Add(33 * time.Hour), wantStale: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, err := getWorkStatus(store, "primary", tt.now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if status.Working == tt.wantStale || (len(status.Stale) == 1) != tt.wantStale {
+				t.Errorf("getWorkStatus() = %+v, want stale %v", status, tt.wantStale)
+			}
+		})
+	}
+}
+
+func TestWorkRecover(t *testing.T) {
+	day := time.Date(2025, 3, 12, 0, 0, 0, 0, time.Local)
+	at := func(h, m int) time.Time {
+		return day.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute)
+	}
+	newStaleStore := func(t *testing.T) *gcal.MemoryStore {
+		t.Helper()
+
+		store := gcal.NewMemoryStore()
+		if _, err := startWork(store, "primary", workTags{}, at(9, 0)); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := pauseWork(store, "primary", at(12, 0)); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := resumeWork(store, "primary", at(13, 0)); err != nil {
+			t.Fatal(err)
+		}
+
+		return store
+	}
+	tomorrow := at(33, 0)
+
+	tests := []struct {
+		name        string
+		how         string
+		at          string
+		wantErr     bool
+		wantUntil   time.Time
+		wantMinutes int
+	}{
+		{name: "Close at a time of the day of the session", how: recoverAt, at: "18:00", wantUntil: at(18, 0), wantMinutes: 480},
+		{name: "Close at a time of the next day", how: recoverAt, at: "tomorrow 01:00", wantUntil: at(25, 0), wantMinutes: 900},
+		{name: "Close before the start of the session", how: recoverAt, at: "08:00", wantErr: true},
+		{name: "Close at the last activity", how: recoverLastActivity, wantUntil: at(13, 0), wantMinutes: 180},
+		{name: "Discard", how: recoverDiscard},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newStaleStore(t)
+
+			if _, err := startWork(store, "primary", workTags{}, tomorrow); err == nil {
+				t.Fatalf("startWork() with a stale session, want error")
+			}
+			status, err := getWorkStatus(store, "primary", tomorrow)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if status.Working || len(status.Stale) != 1 {
+				t.Fatalf("getWorkStatus() = %+v, want a stale session", status)
+			}
+
+			recovered, err := recoverWork(store, "primary", tt.how, tt.at, tomorrow)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("recoverWork() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if len(recovered) != 1 {
+				t.Fatalf("recoverWork() = %+v, want a session", recovered)
+			}
+			if tt.how == recoverDiscard {
+				if !recovered[0].Discarded {
+					t.Errorf("recoverWork() = %+v, want discarded", recovered[0])
+				}
+			} else if recovered[0].Until != tt.wantUntil.Format(time.RFC3339) || recovered[0].Minutes != tt.wantMinutes {
+				t.Errorf("recoverWork() = %+v, want until %v after %v minutes", recovered[0], tt.wantUntil, tt.wantMinutes)
+			}
+
+			if _, err := startWork(store, "primary", workTags{}, tomorrow); err != nil {
+				t.Errorf("startWork() after recovery error = %v", err)
+			}
+		})
+	}
+}
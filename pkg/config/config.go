@@ -0,0 +1,436 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jiyeol-lee/gcli/pkg/util"
+)
+
+// Config is the content of $XDG_CONFIG_HOME/gcli/config.json
+type Config struct {
+	// CacheTTL is how long fetched events are served from the cache, e.g. "5m"
+	CacheTTL string `json:"cache_ttl,omitempty"`
+	// Calendars are the calendars used when no --calendar is given
+	Calendars []CalendarConfig `json:"calendars,omitempty"`
+	// DefaultProfile is the auth profile used when no --profile is given
+	DefaultProfile string `json:"default_profile,omitempty"`
+	// Profiles are the named auth profiles, each signed into its own account
+	Profiles map[string]ProfileConfig `json:"profiles,omitempty"`
+	// Work configures the work tracker
+	Work WorkConfig `json:"work,omitempty"`
+	// Formats are the default --format templates of the status-bar commands,
+	// by command name, e.g. {"soon": "{{.Summary}} in {{.MinutesUntil}}m"}
+	Formats map[string]string `json:"formats,omitempty"`
+	// Empty is printed by the status-bar commands when there is no event,
+	// "N/A" when unset. Set it to "" to print nothing.
+	Empty *string `json:"empty,omitempty"`
+	// Join configures the join command
+	Join JoinConfig `json:"join,omitempty"`
+	// People are the time zones and working hours of the people scheduled with,
+	// by email
+	People map[string]PersonConfig `json:"people,omitempty"`
+}
+
+// PersonConfig holds the time zone and working hours of someone scheduled with
+type PersonConfig struct {
+	// TimeZone is an IANA time zone, e.g. "Europe/Paris", the local one when empty
+	TimeZone string `json:"time_zone,omitempty"`
+	// Hours are the working hours in the time zone, Monday to Friday, e.g.
+	// "09:00-17:00", the configured work hours when empty
+	Hours string `json:"hours,omitempty"`
+}
+
+// JoinConfig holds the settings of the join command
+type JoinConfig struct {
+	// Window is how soon a meeting must start to be joined, as a duration like
+	// "5m", 5m when empty
+	Window string `json:"window,omitempty"`
+	// Opener is the command opening the meeting link, given as its last
+	// argument, e.g. "firefox --new-window". $BROWSER or the opener of the
+	// platform when empty.
+	Opener string `json:"opener,omitempty"`
+}
+
+// WorkConfig holds the targets of the work tracker, as durations like "8h" or "7h30m"
+type WorkConfig struct {
+	// DailyTarget is the time to work Monday to Friday, 8h when empty
+	DailyTarget string `json:"daily_target,omitempty"`
+	// WeekdayTargets override the daily target by weekday, e.g. {"fri": "6h", "sat": "2h"}
+	WeekdayTargets map[string]string `json:"weekday_targets,omitempty"`
+	// WeeklyTarget is the time to work Monday to Sunday, the sum of the daily
+	// targets when empty
+	WeeklyTarget string `json:"weekly_target,omitempty"`
+	// Hours are the working hours, e.g. "09:00-18:00", on the days with a
+	// target. 09:00-18:00 when empty.
+	Hours string `json:"hours,omitempty"`
+}
+
+// CalendarConfig names a calendar. The label is shown next to its events and
+// can be given to --calendar instead of the id.
+type CalendarConfig struct {
+	Id    string `json:"id"`
+	Label string `json:"label,omitempty"`
+	// Profile is the auth profile the calendar is read with, the default one when empty
+	Profile string `json:"profile,omitempty"`
+}
+
+// ProfileConfig holds the OAuth client of an auth profile. The client is
+// client_id and client_secret, else credentials_file, else the
+// GOOGLE_CLIENT_ID and GOOGLE_CLIENT_SECRET environment variables, else the
+// client_secret.json of the config directory.
+type ProfileConfig struct {
+	ClientID     string `json:"client_id,omitempty"`
+	ClientSecret string `json:"client_secret,omitempty"`
+	// CredentialsFile is a client_secret.json downloaded from the Google Cloud
+	// console, relative to the config directory
+	CredentialsFile string `json:"credentials_file,omitempty"`
+	// Login is how to sign in when no token is stored: browser, manual or
+	// device, chosen from the environment when empty. Google refuses the
+	// Calendar scopes in the device login, which needs a DeviceAuthURL.
+	Login string `json:"login,omitempty"`
+	// CallbackPort is the port of the local callback of the browser login, a
+	// free one when 0
+	CallbackPort int `json:"callback_port,omitempty"`
+	// TokenURL and DeviceAuthURL replace the OAuth endpoints of Google
+	TokenURL      string `json:"token_url,omitempty"`
+	DeviceAuthURL string `json:"device_auth_url,omitempty"`
+}
+
+// DefaultProfileName is the name of the auth profile used when none is configured
+var DefaultProfileName = "default"
+
+var defaultCacheTTL = 5 * time.Minute
+
+var defaultDailyTarget = 8 * time.Hour
+
+var defaultWorkHours = "09:00-18:00"
+
+var defaultEmpty = "N/A"
+
+var defaultJoinWindow = 5 * time.Minute
+
+// Dir function returns the directory of the configuration, $XDG_CONFIG_HOME/gcli
+// or ~/.config/gcli
+func Dir() (string, error) {
+	return xdgDir("XDG_CONFIG_HOME", ".config")
+}
+
+// CacheDir function returns the directory of the cache, $XDG_CACHE_HOME/gcli
+// or ~/.cache/gcli
+func CacheDir() (string, error) {
+	return xdgDir("XDG_CACHE_HOME", ".cache")
+}
+
+func xdgDir(env string, fallback string) (string, error) {
+	if dir := os.Getenv(env); dir != "" {
+		return filepath.Join(dir, "gcli"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, fallback, "gcli"), nil
+}
+
+// Load function reads the configuration file. A missing file is an empty configuration.
+func Load() (*Config, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+
+	return LoadFile(filepath.Join(dir, "config.json"))
+}
+
+// LoadFile function reads the configuration from the given file
+func LoadFile(path string) (*Config, error) {
+	cfg := &Config{}
+
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(b, cfg); err != nil {
+		return nil, fmt.Errorf("error parsing %v: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// GetDefaultProfile method returns the auth profile used when no --profile is given
+func (c *Config) GetDefaultProfile() string {
+	if c == nil || c.DefaultProfile == "" {
+		return DefaultProfileName
+	}
+
+	return c.DefaultProfile
+}
+
+// GetProfile method returns the configuration of the auth profile, empty when not configured
+func (c *Config) GetProfile(name string) ProfileConfig {
+	if c == nil {
+		return ProfileConfig{}
+	}
+
+	return c.Profiles[name]
+}
+
+// ResolveCalendars method returns the calendars to read for the given auth
+// profiles, or the default profile when profiles is empty.
+//
+// Without names, these are the configured calendars of the profiles, and the
+// primary calendar of every profile without configured calendars. Names are
+// configured ids or labels, or other calendar ids which are then read with
+// every profile.
+func (c *Config) ResolveCalendars(names []string, profiles []string) []CalendarConfig {
+	if len(profiles) == 0 {
+		profiles = []string{c.GetDefaultProfile()}
+	}
+
+	var configured []CalendarConfig
+	if c != nil {
+		for _, cal := range c.Calendars {
+			if cal.Profile == "" {
+				cal.Profile = c.GetDefaultProfile()
+			}
+			configured = append(configured, cal)
+		}
+	}
+
+	var calendars []CalendarConfig
+	if len(names) == 0 {
+		for _, profile := range profiles {
+			found := false
+			for _, cal := range configured {
+				if cal.Profile == profile {
+					calendars = append(calendars, cal)
+					found = true
+				}
+			}
+			if !found {
+				calendars = append(calendars, CalendarConfig{Id: "primary", Profile: profile})
+			}
+		}
+
+		return calendars
+	}
+
+	for _, name := range names {
+		idx := slices.IndexFunc(configured, func(cal CalendarConfig) bool {
+			return slices.Contains(profiles, cal.Profile) &&
+				(cal.Id == name || (cal.Label != "" && cal.Label == name))
+		})
+		if idx != -1 {
+			calendars = append(calendars, configured[idx])
+			continue
+		}
+
+		for _, profile := range profiles {
+			calendars = append(calendars, CalendarConfig{Id: name, Profile: profile})
+		}
+	}
+
+	return calendars
+}
+
+// Name method returns the label of the calendar, or its id prefixed with the
+// auth profile when not the default one
+func (c CalendarConfig) Name() string {
+	if c.Label != "" {
+		return c.Label
+	}
+
+	if c.Profile != "" && c.Profile != DefaultProfileName {
+		return c.Profile + "/" + c.Id
+	}
+
+	return c.Id
+}
+
+// profileNamePattern matches the auth profile names, which are used in paths
+var profileNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// ValidateProfileName function returns an error unless the auth profile name
+// is made of letters, digits, "-" and "_" only
+func ValidateProfileName(name string) error {
+	if !profileNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid profile %q, want letters, digits, - and _ only", name)
+	}
+
+	return nil
+}
+
+// TokenPath function returns the file holding the OAuth token of the auth profile
+func TokenPath(profile string) (string, error) {
+	if err := ValidateProfileName(profile); err != nil {
+		return "", err
+	}
+
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "tokens", profile+".json"), nil
+}
+
+// GetCacheTTL method returns the cache TTL, 5 minutes when unset
+func (c *Config) GetCacheTTL() (time.Duration, error) {
+	if c == nil || c.CacheTTL == "" {
+		return defaultCacheTTL, nil
+	}
+
+	ttl, err := time.ParseDuration(c.CacheTTL)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cache_ttl: %w", err)
+	}
+
+	return ttl, nil
+}
+
+// GetWorkTargets method returns the time to work on each weekday, indexed by
+// time.Weekday, and over a week
+func (c *Config) GetWorkTargets() ([7]time.Duration, time.Duration, error) {
+	var daily [7]time.Duration
+	var work WorkConfig
+	if c != nil {
+		work = c.Work
+	}
+
+	target := defaultDailyTarget
+	if work.DailyTarget != "" {
+		d, err := time.ParseDuration(work.DailyTarget)
+		if err != nil {
+			return daily, 0, fmt.Errorf("invalid daily_target: %w", err)
+		}
+		target = d
+	}
+	for day := time.Monday; day <= time.Friday; day++ {
+		daily[day] = target
+	}
+
+	for name, value := range work.WeekdayTargets {
+		idx := -1
+		for day := time.Sunday; day <= time.Saturday; day++ {
+			if strings.EqualFold(name, day.String()) || strings.EqualFold(name, day.String()[:3]) {
+				idx = int(day)
+			}
+		}
+		if idx == -1 {
+			return daily, 0, fmt.Errorf("invalid weekday_targets: unknown weekday %q", name)
+		}
+
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return daily, 0, fmt.Errorf("invalid weekday_targets: %v: %w", name, err)
+		}
+		daily[idx] = d
+	}
+
+	var weekly time.Duration
+	for _, d := range daily {
+		weekly += d
+	}
+	if work.WeeklyTarget != "" {
+		d, err := time.ParseDuration(work.WeeklyTarget)
+		if err != nil {
+			return daily, 0, fmt.Errorf("invalid weekly_target: %w", err)
+		}
+		weekly = d
+	}
+
+	return daily, weekly, nil
+}
+
+// GetWorkHours method returns the start and end of the working hours, as
+// offsets from midnight
+func (c *Config) GetWorkHours() (time.Duration, time.Duration, error) {
+	hours := defaultWorkHours
+	if c != nil && c.Work.Hours != "" {
+		hours = c.Work.Hours
+	}
+
+	start, end, err := util.ParseClockRange(hours)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid work hours: %w", err)
+	}
+
+	return start, end, nil
+}
+
+// GetFormat method returns the configured --format template of the command,
+// empty when unset
+func (c *Config) GetFormat(command string) string {
+	if c == nil {
+		return ""
+	}
+
+	return c.Formats[command]
+}
+
+// GetEmpty method returns what the status-bar commands print when there is no event
+func (c *Config) GetEmpty() string {
+	if c == nil || c.Empty == nil {
+		return defaultEmpty
+	}
+
+	return *c.Empty
+}
+
+// GetJoinWindow method returns how soon a meeting must start to be joined
+func (c *Config) GetJoinWindow() (time.Duration, error) {
+	if c == nil || c.Join.Window == "" {
+		return defaultJoinWindow, nil
+	}
+
+	window, err := time.ParseDuration(c.Join.Window)
+	if err != nil {
+		return 0, fmt.Errorf("invalid join window: %w", err)
+	}
+
+	return window, nil
+}
+
+// GetPerson method returns the time zone and the start and end of the working
+// hours, as offsets from midnight, of someone scheduled with
+func (c *Config) GetPerson(email string) (*time.Location, time.Duration, time.Duration, error) {
+	var person PersonConfig
+	if c != nil {
+		person = c.People[email]
+	}
+
+	loc := time.Local
+	if person.TimeZone != "" {
+		l, err := time.LoadLocation(person.TimeZone)
+		if err != nil {
+			return nil, 0, 0, fmt.Errorf("invalid time zone of %v: %w", email, err)
+		}
+		loc = l
+	}
+
+	if person.Hours == "" {
+		start, end, err := c.GetWorkHours()
+		return loc, start, end, err
+	}
+
+	start, end, err := util.ParseClockRange(person.Hours)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("invalid hours of %v: %w", email, err)
+	}
+
+	return loc, start, end, nil
+}
@@ -0,0 +1,412 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.json")
+	os.WriteFile(valid, []byte(`{"cache_ttl": "1m", "calendars": [{"id": "team@example.com", "label": "team"}]}`), 0600)
+	invalid := filepath.Join(dir, "invalid.json")
+	os.WriteFile(invalid, []byte(`{"cache_ttl": `), 0600)
+
+	type args struct {
+		path string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    *Config
+		wantErr bool
+	}{
+		{
+			name: "When file is missing, return an empty configuration",
+			args: args{path: filepath.Join(dir, "missing.json")},
+			want: &Config{},
+		},
+		{
+			name: "When file is valid, return its content",
+			args: args{path: valid},
+			want: &Config{
+				CacheTTL:  "1m",
+				Calendars: []CalendarConfig{{Id: "team@example.com", Label: "team"}},
+			},
+		},
+		{
+			name:    "When file is invalid, return error",
+			args:    args{path: invalid},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LoadFile(tt.args.path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("LoadFile() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LoadFile() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfigGetCacheTTL(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *Config
+		want    time.Duration
+		wantErr bool
+	}{
+		{
+			name: "When unset, return the default",
+			cfg:  &Config{},
+			want: defaultCacheTTL,
+		},
+		{
+			name: "When configuration is nil, return the default",
+			cfg:  nil,
+			want: defaultCacheTTL,
+		},
+		{
+			name: "When set, return it",
+			cfg:  &Config{CacheTTL: "30s"},
+			want: time.Second * 30,
+		},
+		{
+			name:    "When invalid, return error",
+			cfg:     &Config{CacheTTL: "soon"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.cfg.GetCacheTTL()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetCacheTTL() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GetCacheTTL() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfigResolveCalendars(t *testing.T) {
+	cfg := &Config{
+		Calendars: []CalendarConfig{
+			{Id: "me@example.com", Label: "work"},
+			{Id: "team@example.com", Label: "team"},
+			{Id: "me@gmail.com", Label: "home", Profile: "personal"},
+		},
+	}
+	tests := []struct {
+		name     string
+		cfg      *Config
+		names    []string
+		profiles []string
+		want     []CalendarConfig
+	}{
+		{
+			name: "When nothing is configured nor given, return the primary calendar",
+			cfg:  &Config{},
+			want: []CalendarConfig{{Id: "primary", Profile: "default"}},
+		},
+		{
+			name: "When nothing is given, return the calendars of the default profile",
+			cfg:  cfg,
+			want: []CalendarConfig{
+				{Id: "me@example.com", Label: "work", Profile: "default"},
+				{Id: "team@example.com", Label: "team", Profile: "default"},
+			},
+		},
+		{
+			name:  "Labels and ids are resolved, unknown ids are kept",
+			cfg:   cfg,
+			names: []string{"team", "me@example.com", "oncall@example.com"},
+			want: []CalendarConfig{
+				{Id: "team@example.com", Label: "team", Profile: "default"},
+				{Id: "me@example.com", Label: "work", Profile: "default"},
+				{Id: "oncall@example.com", Profile: "default"},
+			},
+		},
+		{
+			name:     "Profiles without configured calendars read their primary calendar",
+			cfg:      cfg,
+			profiles: []string{"personal", "other"},
+			want: []CalendarConfig{
+				{Id: "me@gmail.com", Label: "home", Profile: "personal"},
+				{Id: "primary", Profile: "other"},
+			},
+		},
+		{
+			name:     "Unknown ids are read with every profile",
+			cfg:      cfg,
+			names:    []string{"home", "primary"},
+			profiles: []string{"personal", "default"},
+			want: []CalendarConfig{
+				{Id: "me@gmail.com", Label: "home", Profile: "personal"},
+				{Id: "primary", Profile: "personal"},
+				{Id: "primary", Profile: "default"},
+			},
+		},
+		{
+			name: "The configured default profile is used when none is given",
+			cfg:  &Config{DefaultProfile: "work"},
+			want: []CalendarConfig{{Id: "primary", Profile: "work"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.ResolveCalendars(tt.names, tt.profiles); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ResolveCalendars() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfigGetWorkTargets(t *testing.T) {
+	h := time.Hour
+	tests := []struct {
+		name       string
+		cfg        *Config
+		wantDaily  [7]time.Duration
+		wantWeekly time.Duration
+		wantErr    bool
+	}{
+		{
+			name:       "When unset, return 8h Monday to Friday",
+			cfg:        &Config{},
+			wantDaily:  [7]time.Duration{0, 8 * h, 8 * h, 8 * h, 8 * h, 8 * h, 0},
+			wantWeekly: 40 * h,
+		},
+		{
+			name:       "When configuration is nil, return the default",
+			cfg:        nil,
+			wantDaily:  [7]time.Duration{0, 8 * h, 8 * h, 8 * h, 8 * h, 8 * h, 0},
+			wantWeekly: 40 * h,
+		},
+		{
+			name: "Weekdays override the daily target",
+			cfg: &Config{Work: WorkConfig{
+				DailyTarget:    "7h30m",
+				WeekdayTargets: map[string]string{"fri": "4h", "Saturday": "1h"},
+			}},
+			wantDaily:  [7]time.Duration{0, 7*h + 30*time.Minute, 7*h + 30*time.Minute, 7*h + 30*time.Minute, 7*h + 30*time.Minute, 4 * h, 1 * h},
+			wantWeekly: 35 * h,
+		},
+		{
+			name:       "The weekly target overrides the sum of the daily targets",
+			cfg:        &Config{Work: WorkConfig{WeeklyTarget: "38h"}},
+			wantDaily:  [7]time.Duration{0, 8 * h, 8 * h, 8 * h, 8 * h, 8 * h, 0},
+			wantWeekly: 38 * h,
+		},
+		{
+			name:    "When a weekday is unknown, return error",
+			cfg:     &Config{Work: WorkConfig{WeekdayTargets: map[string]string{"someday": "1h"}}},
+			wantErr: true,
+		},
+		{
+			name:    "When a target is invalid, return error",
+			cfg:     &Config{Work: WorkConfig{DailyTarget: "a lot"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			daily, weekly, err := tt.cfg.GetWorkTargets()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetWorkTargets() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if daily != tt.wantDaily || weekly != tt.wantWeekly {
+				t.Errorf("GetWorkTargets() = %v, %v, want %v, %v", daily, weekly, tt.wantDaily, tt.wantWeekly)
+			}
+		})
+	}
+}
+
+func TestConfigGetEmpty(t *testing.T) {
+	blank := ""
+	tests := []struct {
+		name string
+		cfg  *Config
+		want string
+	}{
+		{
+			name: "When unset, return the default",
+			cfg:  &Config{},
+			want: defaultEmpty,
+		},
+		{
+			name: "When configuration is nil, return the default",
+			cfg:  nil,
+			want: defaultEmpty,
+		},
+		{
+			name: "When set to an empty string, return it",
+			cfg:  &Config{Empty: &blank},
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.GetEmpty(); got != tt.want {
+				t.Errorf("GetEmpty() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfigGetJoinWindow(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *Config
+		want    time.Duration
+		wantErr bool
+	}{
+		{
+			name: "When unset, return the default",
+			cfg:  &Config{},
+			want: defaultJoinWindow,
+		},
+		{
+			name: "When set, return it",
+			cfg:  &Config{Join: JoinConfig{Window: "10m"}},
+			want: 10 * time.Minute,
+		},
+		{
+			name:    "When invalid, return error",
+			cfg:     &Config{Join: JoinConfig{Window: "soon"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.cfg.GetJoinWindow()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetJoinWindow() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GetJoinWindow() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfigGetWorkHours(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       *Config
+		wantStart time.Duration
+		wantEnd   time.Duration
+		wantErr   bool
+	}{
+		{
+			name:      "When unset, return the default",
+			cfg:       &Config{},
+			wantStart: 9 * time.Hour,
+			wantEnd:   18 * time.Hour,
+		},
+		{
+			name:      "When set, return it",
+			cfg:       &Config{Work: WorkConfig{Hours: "08:30-16:30"}},
+			wantStart: 8*time.Hour + 30*time.Minute,
+			wantEnd:   16*time.Hour + 30*time.Minute,
+		},
+		{
+			name:    "When invalid, return error",
+			cfg:     &Config{Work: WorkConfig{Hours: "all day"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, err := tt.cfg.GetWorkHours()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetWorkHours() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if start != tt.wantStart || end != tt.wantEnd {
+				t.Errorf("GetWorkHours() = %v, %v, want %v, %v", start, end, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestConfigGetPerson(t *testing.T) {
+	cfg := &Config{
+		Work: WorkConfig{Hours: "08:00-16:00"},
+		People: map[string]PersonConfig{
+			"paris@example.com": {TimeZone: "Europe/Paris", Hours: "10:00-18:00"},
+			"bogus@example.com": {TimeZone: "Mars/Olympus"},
+		},
+	}
+	tests := []struct {
+		name      string
+		email     string
+		wantZone  string
+		wantStart time.Duration
+		wantEnd   time.Duration
+		wantErr   bool
+	}{
+		{
+			name:      "When configured, return the time zone and hours",
+			email:     "paris@example.com",
+			wantZone:  "Europe/Paris",
+			wantStart: 10 * time.Hour,
+			wantEnd:   18 * time.Hour,
+		},
+		{
+			name:      "When unknown, return the local time zone and the work hours",
+			email:     "someone@example.com",
+			wantZone:  time.Local.String(),
+			wantStart: 8 * time.Hour,
+			wantEnd:   16 * time.Hour,
+		},
+		{
+			name:    "When the time zone is invalid, return error",
+			email:   "bogus@example.com",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loc, start, end, err := cfg.GetPerson(tt.email)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetPerson() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if loc.String() != tt.wantZone || start != tt.wantStart || end != tt.wantEnd {
+				t.Errorf("GetPerson() = %v, %v, %v, want %v, %v, %v", loc, start, end, tt.wantZone, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestValidateProfileName(t *testing.T) {
+	for _, name := range []string{"default", "work", "my-profile_2"} {
+		if err := ValidateProfileName(name); err != nil {
+			t.Errorf("ValidateProfileName(%q) error = %v, want nil", name, err)
+		}
+	}
+	for _, name := range []string{"", "../../x", "a/b", "work.json", "a b"} {
+		if err := ValidateProfileName(name); err == nil {
+			t.Errorf("ValidateProfileName(%q) error = nil, want an error", name)
+		}
+	}
+}
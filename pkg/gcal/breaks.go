@@ -0,0 +1,144 @@
+package gcal
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+// workBreaksKey is the extended property holding the breaks of a work session,
+// as comma-separated start/end RFC3339 intervals. The break in progress has no end.
+var workBreaksKey string = "WORK_BREAKS"
+
+// Break is a pause within a work session. End is zero while in progress.
+type Break struct {
+	Start time.Time
+	End   time.Time
+}
+
+// GetBreaks method returns the breaks of the work session
+func (_ *Calendar) GetBreaks(event *calendar.Event) ([]Break, error) {
+	if event.ExtendedProperties == nil || event.ExtendedProperties.Private[workBreaksKey] == "" {
+		return nil, nil
+	}
+
+	var breaks []Break
+	for _, interval := range strings.Split(event.ExtendedProperties.Private[workBreaksKey], ",") {
+		start, end, _ := strings.Cut(interval, "/")
+
+		st, err := time.Parse(time.RFC3339, start)
+		if err != nil {
+			return nil, fmt.Errorf("invalid break %q: %w", interval, err)
+		}
+
+		b := Break{Start: st}
+		if end != "" {
+			if b.End, err = time.Parse(time.RFC3339, end); err != nil {
+				return nil, fmt.Errorf("invalid break %q: %w", interval, err)
+			}
+		}
+		breaks = append(breaks, b)
+	}
+
+	return breaks, nil
+}
+
+func (_ *Calendar) setBreaks(event *calendar.Event, breaks []Break) {
+	if event.ExtendedProperties == nil {
+		event.ExtendedProperties = &calendar.EventExtendedProperties{
+			Private: map[string]string{},
+		}
+	}
+
+	intervals := make([]string, 0, len(breaks))
+	for _, b := range breaks {
+		interval := b.Start.Format(time.RFC3339) + "/"
+		if !b.End.IsZero() {
+			interval += b.End.Format(time.RFC3339)
+		}
+		intervals = append(intervals, interval)
+	}
+	event.ExtendedProperties.Private[workBreaksKey] = strings.Join(intervals, ",")
+}
+
+// GetBreakInProgress method returns the break in progress of the work session, if any
+func (c *Calendar) GetBreakInProgress(event *calendar.Event) (*Break, error) {
+	breaks, err := c.GetBreaks(event)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(breaks) == 0 || !breaks[len(breaks)-1].End.IsZero() {
+		return nil, nil
+	}
+
+	return &breaks[len(breaks)-1], nil
+}
+
+// GetBreakDuration method returns the time spent in breaks of the work session
+// until now, the break in progress included
+func (c *Calendar) GetBreakDuration(event *calendar.Event) (time.Duration, error) {
+	breaks, err := c.GetBreaks(event)
+	if err != nil {
+		return 0, err
+	}
+
+	var total time.Duration
+	for _, b := range breaks {
+		end := b.End
+		if end.IsZero() {
+			end = c.now()
+		}
+		total += end.Sub(b.Start)
+	}
+
+	return total, nil
+}
+
+// PausePendingEvent method starts a break in the pending work session
+func (c *Calendar) PausePendingEvent(event *calendar.Event) (*calendar.Event, error) {
+	if event == nil {
+		return nil, fmt.Errorf("event is nil")
+	}
+
+	breaks, err := c.GetBreaks(event)
+	if err != nil {
+		return nil, err
+	}
+	if len(breaks) > 0 && breaks[len(breaks)-1].End.IsZero() {
+		return nil, fmt.Errorf("work session already paused since %v", breaks[len(breaks)-1].Start.Format(time.RFC3339))
+	}
+
+	c.setBreaks(event, append(breaks, Break{Start: c.now()}))
+
+	return c.Store.Update(c.Id, event)
+}
+
+// ResumePendingEvent method ends the break in progress of the pending work session
+func (c *Calendar) ResumePendingEvent(event *calendar.Event) (*calendar.Event, error) {
+	if event == nil {
+		return nil, fmt.Errorf("event is nil")
+	}
+
+	breaks, err := c.GetBreaks(event)
+	if err != nil {
+		return nil, err
+	}
+	if len(breaks) == 0 || !breaks[len(breaks)-1].End.IsZero() {
+		return nil, fmt.Errorf("work session is not paused")
+	}
+
+	breaks[len(breaks)-1].End = c.now()
+	c.setBreaks(event, breaks)
+
+	return c.Store.Update(c.Id, event)
+}
+
+// closedHours function returns the hours stored in WORKING_HOURS for a closed
+// work session. "0.000" marks a pending session, so a closed one is worth at
+// least 0.001, e.g. when its breaks cover it whole.
+func closedHours(worked time.Duration) float64 {
+	return max(worked.Hours(), 0.001)
+}
@@ -0,0 +1,281 @@
+package gcal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/jiyeol-lee/gcli/pkg/util"
+	"golang.org/x/oauth2"
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/googleapi"
+)
+
+// ErrOffline is returned when the store is offline and the request cannot be
+// served from the cache
+var ErrOffline = errors.New("offline")
+
+// cacheRetention is how many TTLs an entry is kept past its fetch, and
+// minCacheRetention the least it is kept, for --offline to be of use
+var (
+	cacheRetention    = 4
+	minCacheRetention = 24 * time.Hour
+)
+
+// CachedStore is an EventStore keeping the listed events on disk, one file per
+// calendar and window of local days. Whole days are fetched, the events of the
+// range being filtered in memory, so that ranges within the same days share an
+// entry. Fresh entries are served without calling the inner store; stale
+// entries are served when the inner store is unreachable.
+type CachedStore struct {
+	// Store is the store the events are fetched from, nil when Offline
+	Store EventStore
+	// Dir is the directory of the cache files
+	Dir string
+	// TTL is how long an entry is fresh
+	TTL time.Duration
+	// Offline serves everything from the cache and refuses writes
+	Offline bool
+	// Warnings receives a line whenever a stale entry is served
+	Warnings io.Writer
+}
+
+type cacheEntry struct {
+	FetchedAt time.Time         `json:"fetched_at"`
+	Items     []*calendar.Event `json:"items"`
+}
+
+func (s *CachedStore) List(
+	calendarId string,
+	from, to time.Time,
+	singleEvents bool,
+) ([]*calendar.Event, error) {
+	first, last := dayWindow(from, to)
+	path := s.entryPath(calendarId, first, last, singleEvents)
+	entry, cacheErr := readCacheEntry(path)
+
+	if s.Offline {
+		if cacheErr != nil {
+			return nil, fmt.Errorf("%w: no cached events for %v from %v to %v", ErrOffline,
+				calendarId, from.Format(time.RFC3339), to.Format(time.RFC3339))
+		}
+		return filterRange(entry.Items, from, to), nil
+	}
+
+	if cacheErr == nil && time.Since(entry.FetchedAt) < s.TTL {
+		return filterRange(entry.Items, from, to), nil
+	}
+
+	items, err := s.Store.List(calendarId, first, last, singleEvents)
+	if err != nil {
+		if cacheErr == nil && isUnreachable(err) {
+			s.warn("calendar API unreachable (%v), using events cached at %v",
+				err, entry.FetchedAt.Local().Format("2006-01-02 15:04"))
+			return filterRange(entry.Items, from, to), nil
+		}
+		return nil, err
+	}
+
+	// The cache is best effort, failing to write it must not fail the listing
+	if err := writeCacheEntry(path, cacheEntry{FetchedAt: time.Now(), Items: items}); err != nil {
+		s.warn("unable to write the event cache: %v", err)
+	}
+	s.prune(calendarId)
+
+	return filterRange(items, from, to), nil
+}
+
+// dayWindow function returns the local midnights starting the first day of
+// the [from, to) range and ending its last day
+func dayWindow(from, to time.Time) (time.Time, time.Time) {
+	first := util.StartOfDay(from.Local())
+	last := util.StartOfDay(to.Local())
+	if last.Before(to) {
+		last = last.AddDate(0, 0, 1)
+	}
+	if !last.After(first) {
+		last = first.AddDate(0, 0, 1)
+	}
+
+	return first, last
+}
+
+// filterRange function returns the events overlapping the [from, to) range.
+// Recurring events, listed as such unless expanded, and events whose times
+// cannot be read are kept, as the API would list them.
+func filterRange(items []*calendar.Event, from, to time.Time) []*calendar.Event {
+	var filtered []*calendar.Event
+	for _, item := range items {
+		within, err := overlaps(item, from, to)
+		if err != nil || within || len(item.Recurrence) > 0 {
+			filtered = append(filtered, item)
+		}
+	}
+
+	return filtered
+}
+
+func (s *CachedStore) Insert(calendarId string, event *calendar.Event) (*calendar.Event, error) {
+	if s.Offline {
+		return nil, fmt.Errorf("%w: unable to create events", ErrOffline)
+	}
+	defer s.invalidate(calendarId)
+
+	return s.Store.Insert(calendarId, event)
+}
+
+func (s *CachedStore) Update(calendarId string, event *calendar.Event) (*calendar.Event, error) {
+	if s.Offline {
+		return nil, fmt.Errorf("%w: unable to update events", ErrOffline)
+	}
+	defer s.invalidate(calendarId)
+
+	return s.Store.Update(calendarId, event)
+}
+
+func (s *CachedStore) Delete(calendarId string, eventId string) error {
+	if s.Offline {
+		return fmt.Errorf("%w: unable to delete events", ErrOffline)
+	}
+	defer s.invalidate(calendarId)
+
+	return s.Store.Delete(calendarId, eventId)
+}
+
+func (s *CachedStore) ListCalendars() ([]*calendar.CalendarListEntry, error) {
+	if s.Offline {
+		return nil, fmt.Errorf("%w: unable to list calendars", ErrOffline)
+	}
+
+	lister, ok := s.Store.(CalendarLister)
+	if !ok {
+		return nil, fmt.Errorf("the store cannot list calendars")
+	}
+
+	return lister.ListCalendars()
+}
+
+func (s *CachedStore) calendarDir(calendarId string) string {
+	return filepath.Join(s.Dir, url.PathEscape(calendarId))
+}
+
+func (s *CachedStore) entryPath(calendarId string, first, last time.Time, singleEvents bool) string {
+	layout := "20060102"
+	name := fmt.Sprintf("%v_%v", first.Format(layout), last.Format(layout))
+	if singleEvents {
+		name += "_single"
+	}
+
+	return filepath.Join(s.calendarDir(calendarId), name+".json")
+}
+
+// invalidate method drops the cached events of the calendar after a write
+func (s *CachedStore) invalidate(calendarId string) {
+	if err := os.RemoveAll(s.calendarDir(calendarId)); err != nil {
+		s.warn("unable to clear the event cache: %v", err)
+	}
+}
+
+// prune method deletes the entries of the calendar fetched longer ago than
+// the retention, so that the cache does not grow without limit
+func (s *CachedStore) prune(calendarId string) {
+	retention := max(time.Duration(cacheRetention)*s.TTL, minCacheRetention)
+
+	files, err := os.ReadDir(s.calendarDir(calendarId))
+	if err != nil {
+		return
+	}
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".json" {
+			continue
+		}
+
+		path := filepath.Join(s.calendarDir(calendarId), file.Name())
+		entry, err := readCacheEntry(path)
+		if err == nil && time.Since(entry.FetchedAt) < retention {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			s.warn("unable to clear the event cache: %v", err)
+		}
+	}
+}
+
+func (s *CachedStore) warn(format string, args ...any) {
+	if s.Warnings == nil {
+		return
+	}
+
+	fmt.Fprintf(s.Warnings, "gcli: warning: "+format+"\n", args...)
+}
+
+func readCacheEntry(path string) (cacheEntry, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return cacheEntry{}, err
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(b, &entry); err != nil {
+		return cacheEntry{}, err
+	}
+
+	return entry, nil
+}
+
+// writeCacheEntry function writes the entry to a temporary file renamed over
+// the entry so that readers never see a partial file
+func writeCacheEntry(path string, entry cacheEntry) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), ".entry-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
+
+// isUnreachable function reports whether the error means the API could not be
+// reached, as opposed to the API refusing the request
+func isUnreachable(err error) bool {
+	var rerr *oauth2.RetrieveError
+	if errors.As(err, &rerr) {
+		return false
+	}
+
+	var gerr *googleapi.Error
+	if errors.As(err, &gerr) {
+		return gerr.Code >= 500
+	}
+
+	var nerr net.Error
+	if errors.As(err, &nerr) {
+		return true
+	}
+
+	var uerr *url.Error
+	return errors.As(err, &uerr)
+}
@@ -0,0 +1,241 @@
+package gcal
+
+import (
+	"bytes"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/googleapi"
+)
+
+// failingStore is an EventStore whose List fails with err once set
+type failingStore struct {
+	*MemoryStore
+	err   error
+	calls int
+}
+
+func (s *failingStore) List(
+	calendarId string,
+	from, to time.Time,
+	singleEvents bool,
+) ([]*calendar.Event, error) {
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return s.MemoryStore.List(calendarId, from, to, singleEvents)
+}
+
+func TestCachedStoreList(t *testing.T) {
+	from := time.Date(2025, 3, 12, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+	unreachable := &url.Error{Op: "Get", URL: "https://www.googleapis.com", Err: errors.New("no route to host")}
+	forbidden := &googleapi.Error{Code: 403}
+
+	tests := []struct {
+		name        string
+		ttl         time.Duration
+		offline     bool
+		primed      bool
+		err         error
+		wantCalls   int
+		wantErr     bool
+		wantWarning bool
+	}{
+		{
+			name:      "When cache is fresh, the inner store is not called",
+			ttl:       time.Hour,
+			primed:    true,
+			wantCalls: 1,
+		},
+		{
+			name:      "When cache is stale, the inner store is called",
+			ttl:       0,
+			primed:    true,
+			wantCalls: 2,
+		},
+		{
+			name:        "When API is unreachable, stale cache is served with a warning",
+			ttl:         0,
+			primed:      true,
+			err:         unreachable,
+			wantCalls:   2,
+			wantWarning: true,
+		},
+		{
+			name:      "When API refuses the request, the error is returned",
+			ttl:       0,
+			primed:    true,
+			err:       forbidden,
+			wantCalls: 2,
+			wantErr:   true,
+		},
+		{
+			name:      "When API is unreachable and nothing is cached, the error is returned",
+			err:       unreachable,
+			wantCalls: 1,
+			wantErr:   true,
+		},
+		{
+			name:      "When offline, cache is served whatever its age",
+			ttl:       0,
+			offline:   true,
+			primed:    true,
+			wantCalls: 1,
+		},
+		{
+			name:      "When offline and nothing is cached, return error",
+			offline:   true,
+			wantCalls: 0,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inner := &failingStore{MemoryStore: NewMemoryStore()}
+			inner.Insert("primary", &calendar.Event{
+				Summary: "Standup",
+				Start:   &calendar.EventDateTime{DateTime: "2025-03-12T09:30:00Z"},
+				End:     &calendar.EventDateTime{DateTime: "2025-03-12T09:45:00Z"},
+			})
+			var warnings bytes.Buffer
+			s := &CachedStore{Store: inner, Dir: t.TempDir(), TTL: tt.ttl, Warnings: &warnings}
+
+			if tt.primed {
+				if _, err := s.List("primary", from, to, true); err != nil {
+					t.Fatal(err)
+				}
+			}
+			inner.err = tt.err
+			s.Offline = tt.offline
+
+			got, err := s.List("primary", from, to, true)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("List() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if inner.calls != tt.wantCalls {
+				t.Errorf("List() called the inner store %v times, want %v", inner.calls, tt.wantCalls)
+			}
+			if !tt.wantErr && (len(got) != 1 || got[0].Summary != "Standup") {
+				t.Errorf("List() = %v, want Standup", got)
+			}
+			if hasWarning := strings.Contains(warnings.String(), "warning"); hasWarning != tt.wantWarning {
+				t.Errorf("List() warnings = %q, want warning %v", warnings.String(), tt.wantWarning)
+			}
+		})
+	}
+}
+
+func TestCachedStoreInsert(t *testing.T) {
+	from := time.Date(2025, 3, 12, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+	s := &CachedStore{Store: NewMemoryStore(), Dir: t.TempDir(), TTL: time.Hour}
+
+	if _, err := s.List("primary", from, to, true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Insert("primary", &calendar.Event{
+		Summary: "Standup",
+		Start:   &calendar.EventDateTime{DateTime: "2025-03-12T09:30:00Z"},
+		End:     &calendar.EventDateTime{DateTime: "2025-03-12T09:45:00Z"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := s.List("primary", from, to, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 {
+		t.Errorf("List() after Insert() = %v, want the inserted event", got)
+	}
+
+	s.Offline = true
+	if _, err := s.Insert("primary", &calendar.Event{}); !errors.Is(err, ErrOffline) {
+		t.Errorf("Insert() offline error = %v, want ErrOffline", err)
+	}
+}
+
+func TestCachedStoreListDayWindow(t *testing.T) {
+	day := time.Date(2025, 3, 12, 0, 0, 0, 0, time.Local)
+	inner := &failingStore{MemoryStore: NewMemoryStore()}
+	for _, e := range []*calendar.Event{
+		{Summary: "Standup", Start: &calendar.EventDateTime{DateTime: day.Add(9 * time.Hour).Format(time.RFC3339)}, End: &calendar.EventDateTime{DateTime: day.Add(10 * time.Hour).Format(time.RFC3339)}},
+		{Summary: "Review", Start: &calendar.EventDateTime{DateTime: day.Add(15 * time.Hour).Format(time.RFC3339)}, End: &calendar.EventDateTime{DateTime: day.Add(16 * time.Hour).Format(time.RFC3339)}},
+	} {
+		inner.Insert("primary", e)
+	}
+	dir := t.TempDir()
+	s := &CachedStore{Store: inner, Dir: dir, TTL: time.Hour}
+
+	// Ranges within the same day share the entry of the whole day
+	for _, tt := range []struct {
+		from, to time.Time
+		want     []string
+	}{
+		{from: day.Add(8 * time.Hour), to: day.AddDate(0, 0, 1), want: []string{"Standup", "Review"}},
+		{from: day.Add(12*time.Hour + 34*time.Second), to: day.AddDate(0, 0, 1), want: []string{"Review"}},
+		{from: day, to: day.Add(9*time.Hour + 30*time.Minute), want: []string{"Standup"}},
+	} {
+		got, err := s.List("primary", tt.from, tt.to, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var summaries []string
+		for _, e := range got {
+			summaries = append(summaries, e.Summary)
+		}
+		if strings.Join(summaries, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("List(%v, %v) = %v, want %v", tt.from, tt.to, summaries, tt.want)
+		}
+	}
+	if inner.calls != 1 {
+		t.Errorf("List() called the inner store %v times, want 1", inner.calls)
+	}
+
+	// Entries older than the retention are deleted on the next write
+	old := filepath.Join(dir, "primary", "20250101_20250102_single.json")
+	if err := writeCacheEntry(old, cacheEntry{FetchedAt: time.Now().Add(-48 * time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.List("primary", day.AddDate(0, 0, 1), day.AddDate(0, 0, 2), true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(old); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("old entry not deleted, stat error = %v", err)
+	}
+	files, _ := os.ReadDir(filepath.Join(dir, "primary"))
+	if len(files) != 2 {
+		t.Errorf("cache has %v entries, want 2", len(files))
+	}
+}
+
+func TestCachedStorePendingEvents(t *testing.T) {
+	now := time.Date(2025, 3, 12, 9, 0, 0, 0, time.Local)
+	inner := &failingStore{MemoryStore: NewMemoryStore()}
+	s := &CachedStore{Store: inner, Dir: t.TempDir(), TTL: time.Hour}
+	c := &Calendar{Id: "primary", Store: s, Now: func() time.Time { return now }}
+
+	if _, err := c.GetPendingEvents(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Later the same day, offline, the pending sessions come from the cache
+	now = now.Add(17 * time.Minute)
+	s.Offline = true
+	if _, err := c.GetPendingEvents(); err != nil {
+		t.Errorf("GetPendingEvents() offline error = %v", err)
+	}
+	if inner.calls != 1 {
+		t.Errorf("GetPendingEvents() called the inner store %v times, want 1", inner.calls)
+	}
+}
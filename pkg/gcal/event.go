@@ -0,0 +1,124 @@
+package gcal
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+var allDayLayout = "2006-01-02"
+
+// IsAllDayEvent function reports whether the event spans whole days instead of a time range
+func IsAllDayEvent(event *calendar.Event) bool {
+	return event.Start != nil && event.Start.DateTime == "" && event.Start.Date != ""
+}
+
+// ParseEventTime function parses the start or end of an event. All-day dates are midnight local time.
+func ParseEventTime(edt *calendar.EventDateTime) (time.Time, error) {
+	if edt == nil {
+		return time.Time{}, fmt.Errorf("event time is missing")
+	}
+
+	if edt.DateTime != "" {
+		t, err := time.Parse(time.RFC3339, edt.DateTime)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("error parsing event time: %w", err)
+		}
+		return t, nil
+	}
+
+	t, err := time.ParseInLocation(allDayLayout, edt.Date, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error parsing event date: %w", err)
+	}
+
+	return t, nil
+}
+
+// videoLinkPattern matches the links of the Zoom, Teams, Meet and Webex meetings
+var videoLinkPattern = regexp.MustCompile(
+	`https://(` +
+		`[\w.-]*zoom\.us/(j|my|w)/[^\s"'<>]+` +
+		`|teams\.microsoft\.com/l/meetup-join/[^\s"'<>]+` +
+		`|teams\.live\.com/meet/[^\s"'<>]+` +
+		`|meet\.google\.com/[a-z]{3}-[a-z]{4}-[a-z]{3}` +
+		`|[\w.-]+\.webex\.com/[^\s"'<>]+` +
+		`)`,
+)
+
+// GetMeetLink function returns the video meeting link of the event, if any:
+// the video entry point of its conference, its Hangout link, or else the first
+// Zoom, Teams, Meet or Webex link of its location or description
+func GetMeetLink(event *calendar.Event) string {
+	if event.ConferenceData != nil {
+		for _, ep := range event.ConferenceData.EntryPoints {
+			if ep.EntryPointType == "video" && ep.Uri != "" {
+				return ep.Uri
+			}
+		}
+	}
+
+	if event.HangoutLink != "" {
+		return event.HangoutLink
+	}
+
+	for _, text := range []string{event.Location, event.Description} {
+		if link := videoLinkPattern.FindString(text); link != "" {
+			// Descriptions are HTML, where & is escaped
+			return strings.ReplaceAll(link, "&amp;", "&")
+		}
+	}
+
+	return ""
+}
+
+// EventInput holds the fields of an event to create
+type EventInput struct {
+	Summary     string
+	Location    string
+	Description string
+	Start       time.Time
+	End         time.Time
+	AllDay      bool
+	// Attendees are the emails of the people invited to the event
+	Attendees []string
+}
+
+// AddEvent method creates an event. For all-day events only the dates of
+// Start and End are used, End being exclusive.
+func (c *Calendar) AddEvent(in EventInput) (*calendar.Event, error) {
+	if in.Summary == "" {
+		return nil, fmt.Errorf("summary is required")
+	}
+	if !in.Start.Before(in.End) {
+		return nil, fmt.Errorf("event must end after it starts")
+	}
+
+	event := &calendar.Event{
+		Summary:     in.Summary,
+		Location:    in.Location,
+		Description: in.Description,
+		Start:       &calendar.EventDateTime{},
+		End:         &calendar.EventDateTime{},
+	}
+	for _, email := range in.Attendees {
+		event.Attendees = append(event.Attendees, &calendar.EventAttendee{Email: email})
+	}
+	if in.AllDay {
+		event.Start.Date = in.Start.Format(allDayLayout)
+		event.End.Date = in.End.Format(allDayLayout)
+	} else {
+		event.Start.DateTime = in.Start.Format(time.RFC3339)
+		event.End.DateTime = in.End.Format(time.RFC3339)
+	}
+
+	evt, err := c.Store.Insert(c.Id, event)
+	if err != nil {
+		return nil, err
+	}
+
+	return evt, nil
+}
@@ -0,0 +1,56 @@
+package gcal
+
+import (
+	"testing"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+func TestGetMeetLink(t *testing.T) {
+	tests := []struct {
+		name  string
+		event *calendar.Event
+		want  string
+	}{
+		{
+			name: "The video entry point of the conference wins",
+			event: &calendar.Event{
+				HangoutLink: "https://meet.google.com/aaa-bbbb-ccc",
+				ConferenceData: &calendar.ConferenceData{EntryPoints: []*calendar.EntryPoint{
+					{EntryPointType: "phone", Uri: "tel:+1-555-0100"},
+					{EntryPointType: "video", Uri: "https://acme.zoom.us/j/123456789"},
+				}},
+			},
+			want: "https://acme.zoom.us/j/123456789",
+		},
+		{
+			name:  "The Hangout link is used without conference",
+			event: &calendar.Event{HangoutLink: "https://meet.google.com/aaa-bbbb-ccc"},
+			want:  "https://meet.google.com/aaa-bbbb-ccc",
+		},
+		{
+			name:  "A Zoom link is found in the location",
+			event: &calendar.Event{Location: "Room 1 / https://us02web.zoom.us/j/85512345678?pwd=abc"},
+			want:  "https://us02web.zoom.us/j/85512345678?pwd=abc",
+		},
+		{
+			name: "A Teams link is found in the HTML description",
+			event: &calendar.Event{
+				Description: `Join: <a href="https://teams.microsoft.com/l/meetup-join/19%3ameeting_abc%40thread.v2/0?context=x&amp;tenant=y">here</a>`,
+			},
+			want: "https://teams.microsoft.com/l/meetup-join/19%3ameeting_abc%40thread.v2/0?context=x&tenant=y",
+		},
+		{
+			name:  "Other links are not video links",
+			event: &calendar.Event{Description: "Agenda: https://docs.example.com/agenda"},
+			want:  "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetMeetLink(tt.event); got != tt.want {
+				t.Errorf("GetMeetLink() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
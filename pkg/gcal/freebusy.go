@@ -0,0 +1,163 @@
+package gcal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jiyeol-lee/gcli/pkg/util"
+	"google.golang.org/api/calendar/v3"
+)
+
+// Period is the [Start, End) range of a busy or free time
+type Period struct {
+	Start time.Time
+	End   time.Time
+}
+
+// BusyLister is implemented by the stores knowing when calendars are busy
+type BusyLister interface {
+	// FreeBusy returns the busy periods of the calendars overlapping the
+	// [from, to) range, by calendar id
+	FreeBusy(calendarIds []string, from, to time.Time) (map[string][]Period, error)
+}
+
+func (s *GoogleStore) FreeBusy(calendarIds []string, from, to time.Time) (map[string][]Period, error) {
+	req := &calendar.FreeBusyRequest{
+		TimeMin: from.Format(time.RFC3339),
+		TimeMax: to.Format(time.RFC3339),
+	}
+	for _, id := range calendarIds {
+		req.Items = append(req.Items, &calendar.FreeBusyRequestItem{Id: id})
+	}
+
+	resp, err := s.Service.Freebusy.Query(req).Context(context.Background()).Do()
+	if err != nil {
+		return nil, err
+	}
+
+	busy := map[string][]Period{}
+	for id, cal := range resp.Calendars {
+		if len(cal.Errors) > 0 {
+			return nil, fmt.Errorf("unable to read the busy times of %v: %v", id, cal.Errors[0].Reason)
+		}
+
+		for _, b := range cal.Busy {
+			st, err := time.Parse(time.RFC3339, b.Start)
+			if err != nil {
+				return nil, fmt.Errorf("invalid busy time of %v: %w", id, err)
+			}
+			et, err := time.Parse(time.RFC3339, b.End)
+			if err != nil {
+				return nil, fmt.Errorf("invalid busy time of %v: %w", id, err)
+			}
+			busy[id] = append(busy[id], Period{Start: st, End: et})
+		}
+	}
+
+	return busy, nil
+}
+
+func (s *CachedStore) FreeBusy(calendarIds []string, from, to time.Time) (map[string][]Period, error) {
+	if s.Offline {
+		return nil, fmt.Errorf("%w: unable to query busy times", ErrOffline)
+	}
+
+	lister, ok := s.Store.(BusyLister)
+	if !ok {
+		return nil, fmt.Errorf("the store cannot query busy times")
+	}
+
+	return lister.FreeBusy(calendarIds, from, to)
+}
+
+func (s *MemoryStore) FreeBusy(calendarIds []string, from, to time.Time) (map[string][]Period, error) {
+	busy := map[string][]Period{}
+	for _, id := range calendarIds {
+		items, err := s.List(id, from, to, true)
+		if err != nil {
+			return nil, err
+		}
+		busy[id] = BusyPeriods(items)
+	}
+
+	return busy, nil
+}
+
+// BusyPeriods function returns the merged periods of the events blocking time,
+// leaving out the transparent and cancelled events and the declined invitations
+func BusyPeriods(events []*calendar.Event) []Period {
+	var periods []Period
+	for _, item := range events {
+		if item.Transparency == "transparent" || item.Status == "cancelled" || isDeclined(item) {
+			continue
+		}
+
+		st, err := ParseEventTime(item.Start)
+		if err != nil {
+			continue
+		}
+		et, err := ParseEventTime(item.End)
+		if err != nil {
+			continue
+		}
+		periods = append(periods, Period{Start: st, End: et})
+	}
+
+	return MergePeriods(periods)
+}
+
+// isDeclined function reports whether the user declined the invitation to the event
+func isDeclined(event *calendar.Event) bool {
+	for _, a := range event.Attendees {
+		if a.Self {
+			return a.ResponseStatus == "declined"
+		}
+	}
+
+	return false
+}
+
+// MergePeriods function returns the periods sorted by start, the overlapping
+// and adjacent ones merged
+func MergePeriods(periods []Period) []Period {
+	sorted := slices.Clone(periods)
+	slices.SortFunc(sorted, func(a, b Period) int {
+		return a.Start.Compare(b.Start)
+	})
+
+	var merged []Period
+	for _, p := range sorted {
+		if n := len(merged); n > 0 && !p.Start.After(merged[n-1].End) {
+			merged[n-1].End = util.MaxTime(merged[n-1].End, p.End)
+			continue
+		}
+		merged = append(merged, p)
+	}
+
+	return merged
+}
+
+// GetBusy method returns the busy periods of the calendar overlapping the
+// [from, to) range, queried with FreeBusy when the store supports it, or else
+// taken from the listed events, as when offline
+func (c *Calendar) GetBusy(from, to time.Time) ([]Period, error) {
+	if lister, ok := c.Store.(BusyLister); ok {
+		busy, err := lister.FreeBusy([]string{c.Id}, from, to)
+		if err == nil {
+			return MergePeriods(busy[c.Id]), nil
+		}
+		if !errors.Is(err, ErrOffline) {
+			return nil, err
+		}
+	}
+
+	evts, err := c.GetEvents(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return BusyPeriods(evts.Items), nil
+}
@@ -0,0 +1,81 @@
+package gcal
+
+import (
+	"testing"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+func TestBusyPeriods(t *testing.T) {
+	day := time.Date(2025, 3, 12, 0, 0, 0, 0, time.Local)
+	at := func(h, m int) time.Time {
+		return day.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute)
+	}
+	event := func(start, end time.Time) *calendar.Event {
+		return &calendar.Event{
+			Start: &calendar.EventDateTime{DateTime: start.Format(time.RFC3339)},
+			End:   &calendar.EventDateTime{DateTime: end.Format(time.RFC3339)},
+		}
+	}
+
+	transparent := event(at(8, 0), at(18, 0))
+	transparent.Transparency = "transparent"
+	declined := event(at(16, 0), at(17, 0))
+	declined.Attendees = []*calendar.EventAttendee{
+		{Email: "boss@example.com", ResponseStatus: "accepted"},
+		{Email: "me@example.com", Self: true, ResponseStatus: "declined"},
+	}
+
+	got := BusyPeriods([]*calendar.Event{
+		event(at(10, 0), at(11, 0)),
+		transparent,
+		event(at(9, 0), at(9, 30)),
+		event(at(10, 30), at(12, 0)),
+		event(at(12, 0), at(12, 30)),
+		declined,
+	})
+	want := []Period{
+		{Start: at(9, 0), End: at(9, 30)},
+		{Start: at(10, 0), End: at(12, 30)},
+	}
+	if !periodsEqual(got, want) {
+		t.Errorf("BusyPeriods() = %v, want %v", got, want)
+	}
+}
+
+func TestCalendarGetBusy(t *testing.T) {
+	day := time.Date(2025, 3, 12, 0, 0, 0, 0, time.Local)
+	store := NewMemoryStore()
+	if _, err := store.Insert("primary", &calendar.Event{
+		Start: &calendar.EventDateTime{DateTime: day.Add(9 * time.Hour).Format(time.RFC3339)},
+		End:   &calendar.EventDateTime{DateTime: day.Add(10 * time.Hour).Format(time.RFC3339)},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &Calendar{Id: "primary", Store: store, Now: func() time.Time { return day }}
+	got, err := c.GetBusy(day, day.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("GetBusy() error = %v", err)
+	}
+	want := []Period{{Start: day.Add(9 * time.Hour), End: day.Add(10 * time.Hour)}}
+	if !periodsEqual(got, want) {
+		t.Errorf("GetBusy() = %v, want %v", got, want)
+	}
+}
+
+// periodsEqual function reports whether the periods are the same instants,
+// whatever their locations
+func periodsEqual(a, b []Period) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Start.Equal(b[i].Start) || !a[i].End.Equal(b[i].End) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -1,7 +1,6 @@
 package gcal
 
 import (
-	"context"
 	"fmt"
 	"log"
 	"regexp"
@@ -13,50 +12,70 @@ import (
 	"github.com/jiyeol-lee/gcli/pkg/goauth"
 	"github.com/jiyeol-lee/gcli/pkg/util"
 	"google.golang.org/api/calendar/v3"
-	"google.golang.org/api/option"
 )
 
 var (
 	workingHoursKey      string = "WORKING_HOURS"
 	totalWorkingHoursKey string = "TOTAL_WORKING_HOURS"
+	workProjectKey       string = "WORK_PROJECT"
+	workTaskKey          string = "WORK_TASK"
 )
 
 type Calendar struct {
-	Id      string
-	Service *calendar.Service
+	Id    string
+	Store EventStore
+	// Now returns the current time, time.Now when nil
+	Now func() time.Time
 }
 
-func (c *Calendar) Initialize() {
+// Initialize method creates the Google Calendar store
+func (c *Calendar) Initialize() error {
 	if c.Id == "" {
-		log.Fatalf("Calendar ID is required")
+		return fmt.Errorf("calendar ID is required")
 	}
 
-	o := goauth.OAuth{}
-
-	err := o.SetClient(calendar.CalendarEventsScope, calendar.CalendarReadonlyScope)
+	store, err := NewGoogleStore(&goauth.OAuth{})
 	if err != nil {
-		log.Fatalf("Unable to set client: %v", err)
+		return err
 	}
 
-	ctx := context.Background()
+	c.Store = store
 
-	svc, err := calendar.NewService(ctx, option.WithHTTPClient(o.Client))
-	if err != nil {
-		log.Fatalf("Unable to create service: %v", err)
+	return nil
+}
+
+func (c *Calendar) now() time.Time {
+	if c.Now == nil {
+		return time.Now()
 	}
 
-	c.Service = svc
+	return c.Now()
 }
 
+// GetTodayEvents method returns the events of today
 func (c *Calendar) GetTodayEvents(onlySingleEvent bool) (*calendar.Events, error) {
-	tmin := util.StartOfDayTime()
-	tmax := util.EndOfDayTime()
+	tmin := util.StartOfDay(c.now())
+	tmax := tmin.AddDate(0, 0, 1)
+
+	return c.listEvents(tmin, tmax, onlySingleEvent)
+}
+
+// GetEvents method returns the events overlapping the [from, to) range, with
+// recurring events expanded into single events and sorted by start time
+func (c *Calendar) GetEvents(from, to time.Time) (*calendar.Events, error) {
+	if !from.Before(to) {
+		return nil, fmt.Errorf("invalid range: %v is not before %v", from.Format(time.RFC3339), to.Format(time.RFC3339))
+	}
+
+	return c.listEvents(from, to, true)
+}
 
-	evts, err := c.Service.Events.List(c.Id).ShowDeleted(false).
-		SingleEvents(onlySingleEvent).TimeMin(tmin).TimeMax(tmax).Do()
+func (c *Calendar) listEvents(from, to time.Time, onlySingleEvent bool) (*calendar.Events, error) {
+	items, err := c.Store.List(c.Id, from, to, onlySingleEvent)
 	if err != nil {
 		return nil, err
 	}
+	evts := &calendar.Events{Items: items}
 
 	// Filter out
 	// 1. expired recurring events
@@ -73,7 +92,7 @@ func (c *Calendar) GetTodayEvents(onlySingleEvent bool) (*calendar.Events, error
 			if idx != -1 {
 				continue
 			}
-			n := time.Now()
+			n := c.now()
 			rec := util.FindUntilFromRecurrence(v.Recurrence)
 			recT, err := util.ParseUntilStringToTime(rec)
 			if rec == "" || (err != nil && recT.Before(n)) {
@@ -85,29 +104,36 @@ func (c *Calendar) GetTodayEvents(onlySingleEvent bool) (*calendar.Events, error
 		return filteredEvts
 	}()
 
-	// Sort the events by start time
-	slices.SortFunc(evts.Items, func(a, b *calendar.Event) int {
-		start1 := a.Start
-		start2 := b.Start
+	// Sort the events by start time, all-day events first
+	slices.SortStableFunc(evts.Items, func(a, b *calendar.Event) int {
+		start1, err1 := ParseEventTime(a.Start)
+		start2, err2 := ParseEventTime(b.Start)
 
-		if start1 == nil && start2 != nil {
+		if err1 != nil && err2 != nil {
+			return 0
+		}
+
+		if err1 != nil {
 			return -1
 		}
 
-		if start1 != nil && start2 == nil {
+		if err2 != nil {
 			return 1
 		}
 
-		if start1.DateTime == "" || start2.DateTime == "" {
-			return 0
+		if cmp := start1.Compare(start2); cmp != 0 {
+			return cmp
 		}
 
-		gap, err := util.CalculateTimeGap(start1.DateTime, start2.DateTime)
-		if err != nil {
-			return 0
+		if IsAllDayEvent(a) && !IsAllDayEvent(b) {
+			return -1
 		}
 
-		return -int(gap.Minutes())
+		if !IsAllDayEvent(a) && IsAllDayEvent(b) {
+			return 1
+		}
+
+		return 0
 	})
 
 	return evts, nil
@@ -153,22 +179,63 @@ func (_ *Calendar) setTotalWorkingHoursProperty(event *calendar.Event, totalWork
 	)
 }
 
-func (c *Calendar) GetTodayPendingEvent(events *calendar.Events) (*calendar.Event, error) {
-	var pendingEvent *calendar.Event
-	for _, item := range events.Items {
-		if c.GetWorkingHoursProperty(item) == "0.000" {
-			pendingEvent = item
-			break
+// GetWorkProject method returns the project the work session is tagged with
+func (_ *Calendar) GetWorkProject(event *calendar.Event) string {
+	if event.ExtendedProperties == nil {
+		return ""
+	}
+
+	return event.ExtendedProperties.Private[workProjectKey]
+}
+
+// GetWorkTask method returns the task the work session is tagged with
+func (_ *Calendar) GetWorkTask(event *calendar.Event) string {
+	if event.ExtendedProperties == nil {
+		return ""
+	}
+
+	return event.ExtendedProperties.Private[workTaskKey]
+}
+
+func (_ *Calendar) setWorkTags(event *calendar.Event, project, task string) {
+	if project == "" && task == "" {
+		return
+	}
+
+	if event.ExtendedProperties == nil {
+		event.ExtendedProperties = &calendar.EventExtendedProperties{
+			Private: map[string]string{},
 		}
 	}
+	if project != "" {
+		event.ExtendedProperties.Private[workProjectKey] = project
+	}
+	if task != "" {
+		event.ExtendedProperties.Private[workTaskKey] = task
+	}
+}
+
+// workSummary method returns the summary of the work session, suffixed with
+// its project and task, e.g. "Working - acme: fix login"
+func (c *Calendar) workSummary(summary string, event *calendar.Event) string {
+	project, task := c.GetWorkProject(event), c.GetWorkTask(event)
+
+	switch {
+	case project != "" && task != "":
+		return fmt.Sprintf("%v - %v: %v", summary, project, task)
+	case project != "":
+		return fmt.Sprintf("%v - %v", summary, project)
+	case task != "":
+		return fmt.Sprintf("%v - %v", summary, task)
+	}
 
-	return pendingEvent, nil
+	return summary
 }
 
 func (c *Calendar) GetTodayTotalWorkingEvent(events *calendar.Events) (*calendar.Event, error) {
 	var totalWorkingHoursEvent *calendar.Event
 	for _, item := range events.Items {
-		if matched, err := regexp.MatchString("^\\d.\\d{3}$", c.GetTotalWorkingHoursProperty(item)); matched == true &&
+		if matched, err := regexp.MatchString("^\\d+\\.\\d{3}$", c.GetTotalWorkingHoursProperty(item)); matched == true &&
 			err == nil {
 			totalWorkingHoursEvent = item
 			break
@@ -178,8 +245,9 @@ func (c *Calendar) GetTodayTotalWorkingEvent(events *calendar.Events) (*calendar
 	return totalWorkingHoursEvent, nil
 }
 
-func (c *Calendar) AddPendingEvent() (*calendar.Event, error) {
-	currentTime := time.Now().Format(time.RFC3339)
+// AddPendingEvent method starts a work session, optionally tagged with a project and a task
+func (c *Calendar) AddPendingEvent(project, task string) (*calendar.Event, error) {
+	currentTime := c.now().Format(time.RFC3339)
 	event := &calendar.Event{
 		Summary: "Working",
 		Start: &calendar.EventDateTime{
@@ -197,8 +265,10 @@ func (c *Calendar) AddPendingEvent() (*calendar.Event, error) {
 	event.GuestsCanSeeOtherGuests = &boolFalse
 	event.GuestsCanInviteOthers = &boolFalse
 	c.setWorkingHoursProperty(event, 0)
+	c.setWorkTags(event, project, task)
+	event.Summary = c.workSummary(event.Summary, event)
 
-	evt, err := c.Service.Events.Insert(c.Id, event).Do()
+	evt, err := c.Store.Insert(c.Id, event)
 	if err != nil {
 		return nil, err
 	}
@@ -206,31 +276,12 @@ func (c *Calendar) AddPendingEvent() (*calendar.Event, error) {
 	return evt, nil
 }
 
-func (c *Calendar) UpdatePendingEvent(event *calendar.Event) (*calendar.Event, error) {
-	if event == nil {
-		return nil, fmt.Errorf("event is nil")
-	}
-
-	currentTime := time.Now().Format(time.RFC3339)
-	duration, err := util.CalculateTimeGap(event.Start.DateTime, currentTime)
-	if err != nil {
-		return nil, err
-	}
-
-	event.Summary = fmt.Sprintf("Work (%.3f hrs)", duration.Hours())
-	event.End.DateTime = currentTime
-	c.setWorkingHoursProperty(event, duration.Hours())
-
-	evt, err := c.Service.Events.Update(c.Id, event.Id, event).Do()
-	if err != nil {
-		return nil, err
-	}
-
-	return evt, nil
+func (c *Calendar) AddTotalWorkingEvent() (*calendar.Event, error) {
+	return c.addTotalWorkingEvent(c.now())
 }
 
-func (c *Calendar) AddTotalWorkingEvent() (*calendar.Event, error) {
-	currentTime := time.Now()
+// addTotalWorkingEvent method creates the total working event of the day of currentTime
+func (c *Calendar) addTotalWorkingEvent(currentTime time.Time) (*calendar.Event, error) {
 	event := &calendar.Event{
 		Summary: "Total Work",
 		Start: &calendar.EventDateTime{
@@ -260,7 +311,7 @@ func (c *Calendar) AddTotalWorkingEvent() (*calendar.Event, error) {
 	event.GuestsCanInviteOthers = &boolFalse
 	c.setTotalWorkingHoursProperty(event, 0)
 
-	evt, err := c.Service.Events.Insert(c.Id, event).Do()
+	evt, err := c.Store.Insert(c.Id, event)
 	if err != nil {
 		return nil, err
 	}
@@ -268,25 +319,26 @@ func (c *Calendar) AddTotalWorkingEvent() (*calendar.Event, error) {
 	return evt, nil
 }
 
-func (c *Calendar) UpdateTotalWorkingEvent(
-	totalWorkingEvent *calendar.Event,
-	workingEvents *calendar.Events,
-) (*calendar.Event, error) {
-	if totalWorkingEvent == nil {
-		return nil, fmt.Errorf("event is nil")
-	}
+// GetTodayWorkingHours method returns the sum of the hours of the finished work sessions
+func (c *Calendar) GetTodayWorkingHours(events *calendar.Events) float64 {
+	totalWorkingHours, _ := c.sumWorkingHours(events)
 
+	return totalWorkingHours
+}
+
+// sumWorkingHours method sums the hours of the finished work sessions and reports whether a pending one exists
+func (c *Calendar) sumWorkingHours(events *calendar.Events) (float64, bool) {
 	var hasPendingEvent bool = false
 	var totalWorkingHours float64 = 0
-	for _, item := range workingEvents.Items {
+	for _, item := range events.Items {
 		workingHoursPropertyValue := c.GetWorkingHoursProperty(item)
 		if workingHoursPropertyValue == "0.000" {
 			hasPendingEvent = true
-			break
+			continue
 		}
-		if matched, err := regexp.MatchString("^\\d.\\d{3}$", workingHoursPropertyValue); matched == true &&
+		if matched, err := regexp.MatchString("^\\d+\\.\\d{3}$", workingHoursPropertyValue); matched == true &&
 			err == nil {
-			hrs, err := strconv.ParseFloat(workingHoursPropertyValue, 3)
+			hrs, err := strconv.ParseFloat(workingHoursPropertyValue, 64)
 			if err != nil {
 				continue
 			}
@@ -294,6 +346,18 @@ func (c *Calendar) UpdateTotalWorkingEvent(
 		}
 	}
 
+	return totalWorkingHours, hasPendingEvent
+}
+
+func (c *Calendar) UpdateTotalWorkingEvent(
+	totalWorkingEvent *calendar.Event,
+	workingEvents *calendar.Events,
+) (*calendar.Event, error) {
+	if totalWorkingEvent == nil {
+		return nil, fmt.Errorf("event is nil")
+	}
+
+	totalWorkingHours, hasPendingEvent := c.sumWorkingHours(workingEvents)
 	if hasPendingEvent {
 		return nil, fmt.Errorf("pending event exists")
 	}
@@ -302,7 +366,7 @@ func (c *Calendar) UpdateTotalWorkingEvent(
 	c.setTotalWorkingHoursProperty(totalWorkingEvent, totalWorkingHours)
 	totalWorkingEvent.Reminders = nil
 
-	evt, err := c.Service.Events.Update(c.Id, totalWorkingEvent.Id, totalWorkingEvent).Do()
+	evt, err := c.Store.Update(c.Id, totalWorkingEvent)
 	if err != nil {
 		log.Printf("Total Working Event: %v\n", totalWorkingEvent.HtmlLink)
 		return nil, err
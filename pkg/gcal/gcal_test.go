@@ -0,0 +1,132 @@
+package gcal
+
+import (
+	"testing"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+func TestCalendarGetEvents(t *testing.T) {
+	day := time.Date(2025, 3, 12, 0, 0, 0, 0, time.Local)
+	at := func(h, m int) string {
+		return day.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute).Format(time.RFC3339)
+	}
+	store := NewMemoryStore()
+	for _, e := range []*calendar.Event{
+		{Summary: "Lunch", Start: &calendar.EventDateTime{DateTime: at(12, 0)}, End: &calendar.EventDateTime{DateTime: at(13, 0)}},
+		{Summary: "Tomorrow", Start: &calendar.EventDateTime{DateTime: at(33, 0)}, End: &calendar.EventDateTime{DateTime: at(34, 0)}},
+		{Summary: "Holiday", Start: &calendar.EventDateTime{Date: "2025-03-12"}, End: &calendar.EventDateTime{Date: "2025-03-13"}},
+		{Summary: "Standup", Start: &calendar.EventDateTime{DateTime: at(9, 30)}, End: &calendar.EventDateTime{DateTime: at(9, 45)}},
+		{Summary: "Yesterday", Start: &calendar.EventDateTime{DateTime: at(-10, 0)}, End: &calendar.EventDateTime{DateTime: at(-9, 0)}},
+	} {
+		if _, err := store.Insert("primary", e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c := &Calendar{Id: "primary", Store: store}
+
+	type args struct {
+		from time.Time
+		to   time.Time
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "Events of the day are sorted by start time, all-day events first",
+			args: args{from: day, to: day.AddDate(0, 0, 1)},
+			want: []string{"Holiday", "Standup", "Lunch"},
+		},
+		{
+			name: "Events across days are sorted by date and time",
+			args: args{from: day.AddDate(0, 0, -1), to: day.AddDate(0, 0, 2)},
+			want: []string{"Yesterday", "Holiday", "Standup", "Lunch", "Tomorrow"},
+		},
+		{
+			name:    "When range is empty, return error",
+			args:    args{from: day, to: day},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			evts, err := c.GetEvents(tt.args.from, tt.args.to)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetEvents() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			var got []string
+			for _, e := range evts.Items {
+				got = append(got, e.Summary)
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("GetEvents() = %v, want %v", got, tt.want)
+				return
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("GetEvents() = %v, want %v", got, tt.want)
+					return
+				}
+			}
+		})
+	}
+}
+
+func TestCalendarUpdateTotalWorkingEvent(t *testing.T) {
+	session := func(hours string) *calendar.Event {
+		return &calendar.Event{
+			ExtendedProperties: &calendar.EventExtendedProperties{
+				Private: map[string]string{workingHoursKey: hours},
+			},
+		}
+	}
+	type args struct {
+		sessions []*calendar.Event
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Hours of the finished sessions are summed",
+			args: args{sessions: []*calendar.Event{session("1.500"), session("10.250"), {Summary: "Meeting"}}},
+			want: "11.750",
+		},
+		{
+			name:    "When a session is pending, return error",
+			args:    args{sessions: []*calendar.Event{session("1.500"), session("0.000")}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Calendar{Id: "primary", Store: NewMemoryStore()}
+			total, err := c.AddTotalWorkingEvent()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := c.UpdateTotalWorkingEvent(total, &calendar.Events{Items: tt.args.sessions})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UpdateTotalWorkingEvent() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if hrs := c.GetTotalWorkingHoursProperty(got); hrs != tt.want {
+				t.Errorf("UpdateTotalWorkingEvent() = %v, want %v", hrs, tt.want)
+			}
+		})
+	}
+}
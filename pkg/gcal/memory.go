@@ -0,0 +1,140 @@
+package gcal
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"google.golang.org/api/calendar/v3"
+)
+
+// MemoryStore is an EventStore keeping the events in memory. Recurring events
+// are not expanded, so it is meant for single events, e.g. in tests or
+// dry runs.
+type MemoryStore struct {
+	mu        sync.Mutex
+	events    map[string][]*calendar.Event
+	calendars []*calendar.CalendarListEntry
+	nextId    int
+}
+
+// NewMemoryStore function returns an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		events: map[string][]*calendar.Event{},
+	}
+}
+
+// AddCalendar method adds a calendar to the calendar list
+func (s *MemoryStore) AddCalendar(entry *calendar.CalendarListEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calendars = append(s.calendars, entry)
+}
+
+func (s *MemoryStore) ListCalendars() ([]*calendar.CalendarListEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.calendars), nil
+}
+
+func (s *MemoryStore) List(
+	calendarId string,
+	from, to time.Time,
+	_ bool,
+) ([]*calendar.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var items []*calendar.Event
+	for _, event := range s.events[calendarId] {
+		if within, err := overlaps(event, from, to); err == nil && within {
+			items = append(items, cloneEvent(event))
+		}
+	}
+
+	return items, nil
+}
+
+func (s *MemoryStore) Insert(calendarId string, event *calendar.Event) (*calendar.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := cloneEvent(event)
+	if stored.Id == "" {
+		s.nextId++
+		stored.Id = fmt.Sprintf("mem%08d", s.nextId)
+	}
+	if stored.Status == "" {
+		stored.Status = "confirmed"
+	}
+	stored.HtmlLink = "memory://" + calendarId + "/" + stored.Id
+
+	s.events[calendarId] = append(s.events[calendarId], stored)
+
+	return cloneEvent(stored), nil
+}
+
+func (s *MemoryStore) Update(calendarId string, event *calendar.Event) (*calendar.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, e := range s.events[calendarId] {
+		if e.Id == event.Id {
+			stored := cloneEvent(event)
+			stored.HtmlLink = e.HtmlLink
+			s.events[calendarId][i] = stored
+			return cloneEvent(stored), nil
+		}
+	}
+
+	return nil, fmt.Errorf("event %v not found", event.Id)
+}
+
+func (s *MemoryStore) Delete(calendarId string, eventId string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, e := range s.events[calendarId] {
+		if e.Id == eventId {
+			s.events[calendarId] = append(s.events[calendarId][:i], s.events[calendarId][i+1:]...)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("event %v not found", eventId)
+}
+
+// cloneEvent function deep copies an event so that callers cannot modify the stored one
+func cloneEvent(event *calendar.Event) *calendar.Event {
+	b, err := json.Marshal(event)
+	if err != nil {
+		panic(err)
+	}
+
+	clone := &calendar.Event{}
+	if err := json.Unmarshal(b, clone); err != nil {
+		panic(err)
+	}
+
+	return clone
+}
+
+// overlaps function reports whether the event overlaps the [from, to) range.
+// Zero-length events overlap it when they start within the range.
+func overlaps(event *calendar.Event, from, to time.Time) (bool, error) {
+	st, err := ParseEventTime(event.Start)
+	if err != nil {
+		return false, err
+	}
+	et, err := ParseEventTime(event.End)
+	if err != nil {
+		return false, err
+	}
+
+	return st.Before(to) && (et.After(from) || (st.Equal(et) && !st.Before(from))), nil
+}
@@ -0,0 +1,234 @@
+package gcal
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jiyeol-lee/gcli/pkg/util"
+	"google.golang.org/api/calendar/v3"
+)
+
+// PendingLookbackDays is how many days back pending work sessions are searched for
+var PendingLookbackDays = 7
+
+// GetPendingEvents method returns the pending work sessions started within
+// PendingLookbackDays, oldest first. The range is made of whole days so that the
+// listing is served from the cache whatever the time.
+func (c *Calendar) GetPendingEvents() ([]*calendar.Event, error) {
+	today := util.StartOfDay(c.now().Local())
+
+	evts, err := c.listEvents(today.AddDate(0, 0, -PendingLookbackDays), today.AddDate(0, 0, 1), true)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []*calendar.Event
+	for _, item := range evts.Items {
+		if c.GetWorkingHoursProperty(item) == "0.000" {
+			pending = append(pending, item)
+		}
+	}
+
+	return pending, nil
+}
+
+// IsStale method reports whether the pending work session was left open on a
+// previous day, having started before today's local midnight
+func (c *Calendar) IsStale(event *calendar.Event) bool {
+	st, err := ParseEventTime(event.Start)
+	if err != nil {
+		return false
+	}
+
+	return st.Before(util.StartOfDay(c.now().Local()))
+}
+
+// GetLastActivity method returns the last known activity of the pending work
+// session: its start, or the last start or end of its breaks
+func (c *Calendar) GetLastActivity(event *calendar.Event) (time.Time, error) {
+	last, err := ParseEventTime(event.Start)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	breaks, err := c.GetBreaks(event)
+	if err != nil {
+		return time.Time{}, err
+	}
+	for _, b := range breaks {
+		for _, t := range []time.Time{b.Start, b.End} {
+			if t.After(last) {
+				last = t
+			}
+		}
+	}
+
+	return last, nil
+}
+
+// workedBetween function returns the time worked within [from, to) by a
+// session started at st and ended at end, its breaks left out
+func workedBetween(st, end time.Time, breaks []Break, from, to time.Time) time.Duration {
+	overlap := func(s, e time.Time) time.Duration {
+		s, e = util.MaxTime(s, from), util.MinTime(e, to)
+		if !s.Before(e) {
+			return 0
+		}
+		return e.Sub(s)
+	}
+
+	worked := overlap(st, end)
+	for _, b := range breaks {
+		bEnd := b.End
+		if bEnd.IsZero() {
+			bEnd = end
+		}
+		worked -= overlap(b.Start, util.MinTime(bEnd, end))
+	}
+
+	return max(worked, 0)
+}
+
+// GetWorkedDuration method returns the time worked within [from, to) by the
+// pending work session until now, its breaks left out
+func (c *Calendar) GetWorkedDuration(event *calendar.Event, from, to time.Time) (time.Duration, error) {
+	st, err := ParseEventTime(event.Start)
+	if err != nil {
+		return 0, err
+	}
+
+	breaks, err := c.GetBreaks(event)
+	if err != nil {
+		return 0, err
+	}
+
+	return workedBetween(st, c.now(), breaks, from, to), nil
+}
+
+// ClosePendingEvent method ends the pending work session at end. A session
+// crossing midnight is split into an entry per day, the first one being the
+// session itself, and the totals of the days are updated.
+func (c *Calendar) ClosePendingEvent(event *calendar.Event, end time.Time) ([]*calendar.Event, error) {
+	if event == nil {
+		return nil, fmt.Errorf("event is nil")
+	}
+
+	st, err := ParseEventTime(event.Start)
+	if err != nil {
+		return nil, err
+	}
+	if end.Before(st) {
+		return nil, fmt.Errorf("work session cannot end at %v, before its start at %v", end.Format(time.RFC3339), st.Format(time.RFC3339))
+	}
+
+	breaks, err := c.GetBreaks(event)
+	if err != nil {
+		return nil, err
+	}
+	// A session ended while paused ends its break too
+	for i := range breaks {
+		if breaks[i].End.IsZero() || breaks[i].End.After(end) {
+			breaks[i].End = util.MaxTime(breaks[i].Start, end)
+		}
+	}
+
+	var sessions []*calendar.Event
+	for segStart := st; ; {
+		segEnd := util.MinTime(util.StartOfDay(segStart.Local()).AddDate(0, 0, 1), end)
+
+		var segBreaks []Break
+		for _, b := range breaks {
+			if b.Start.Before(segEnd) && b.End.After(segStart) {
+				segBreaks = append(segBreaks, Break{Start: util.MaxTime(b.Start, segStart), End: util.MinTime(b.End, segEnd)})
+			}
+		}
+
+		session := event
+		if len(sessions) > 0 {
+			session = &calendar.Event{
+				Start:                   &calendar.EventDateTime{},
+				End:                     &calendar.EventDateTime{},
+				Visibility:              event.Visibility,
+				Transparency:            event.Transparency,
+				ColorId:                 event.ColorId,
+				GuestsCanSeeOtherGuests: event.GuestsCanSeeOtherGuests,
+				GuestsCanInviteOthers:   event.GuestsCanInviteOthers,
+			}
+			c.setWorkTags(session, c.GetWorkProject(event), c.GetWorkTask(event))
+		}
+		session.Start.DateTime = segStart.Format(time.RFC3339)
+		session.End.DateTime = segEnd.Format(time.RFC3339)
+		if len(segBreaks) > 0 || (len(sessions) == 0 && len(breaks) > 0) {
+			c.setBreaks(session, segBreaks)
+		}
+
+		hours := closedHours(workedBetween(segStart, segEnd, segBreaks, segStart, segEnd))
+		session.Summary = c.workSummary(fmt.Sprintf("Work (%.3f hrs)", hours), session)
+		c.setWorkingHoursProperty(session, hours)
+
+		var saved *calendar.Event
+		if len(sessions) == 0 {
+			saved, err = c.Store.Update(c.Id, session)
+		} else {
+			saved, err = c.Store.Insert(c.Id, session)
+		}
+		if err != nil {
+			return sessions, err
+		}
+		sessions = append(sessions, saved)
+
+		if !segEnd.Before(end) {
+			break
+		}
+		segStart = segEnd
+	}
+
+	for _, session := range sessions {
+		segStart, err := ParseEventTime(session.Start)
+		if err != nil {
+			return sessions, err
+		}
+		if _, err := c.UpdateDayTotal(segStart); err != nil {
+			return sessions, fmt.Errorf("unable to update total working event: %w", err)
+		}
+	}
+
+	return sessions, nil
+}
+
+// DiscardPendingEvent method deletes the pending work session
+func (c *Calendar) DiscardPendingEvent(event *calendar.Event) error {
+	if event == nil {
+		return fmt.Errorf("event is nil")
+	}
+
+	return c.Store.Delete(c.Id, event.Id)
+}
+
+// UpdateDayTotal method recomputes the total working event of the day of t,
+// creating it when missing
+func (c *Calendar) UpdateDayTotal(t time.Time) (*calendar.Event, error) {
+	day := util.StartOfDay(t.Local())
+
+	evts, err := c.listEvents(day, day.AddDate(0, 0, 1), true)
+	if err != nil {
+		return nil, err
+	}
+
+	totalWorkingEvent, err := c.GetTodayTotalWorkingEvent(evts)
+	if err != nil {
+		return nil, err
+	}
+	// The total is updated when the session in progress that day is stopped
+	if _, hasPendingEvent := c.sumWorkingHours(evts); hasPendingEvent {
+		return totalWorkingEvent, nil
+	}
+	if totalWorkingEvent == nil {
+		totalWorkingEvent, err = c.addTotalWorkingEvent(day)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return c.UpdateTotalWorkingEvent(totalWorkingEvent, evts)
+}
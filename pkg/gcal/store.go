@@ -0,0 +1,104 @@
+package gcal
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jiyeol-lee/gcli/pkg/goauth"
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/option"
+)
+
+// EventStore is where the events of the calendars are read from and written to
+type EventStore interface {
+	// List returns the events of the calendar overlapping the [from, to) range.
+	// With singleEvents, recurring events are expanded into their instances.
+	List(calendarId string, from, to time.Time, singleEvents bool) ([]*calendar.Event, error)
+	// Insert creates the event and returns it as stored
+	Insert(calendarId string, event *calendar.Event) (*calendar.Event, error)
+	// Update replaces the event with the same id and returns it as stored
+	Update(calendarId string, event *calendar.Event) (*calendar.Event, error)
+	// Delete removes the event
+	Delete(calendarId string, eventId string) error
+}
+
+// CalendarLister is implemented by the stores knowing the calendars of the account
+type CalendarLister interface {
+	// ListCalendars returns the calendars of the account's calendar list
+	ListCalendars() ([]*calendar.CalendarListEntry, error)
+}
+
+// GoogleStore is the EventStore backed by the Google Calendar API
+type GoogleStore struct {
+	Service *calendar.Service
+}
+
+// NewGoogleStore function authorizes the client and creates the Google Calendar service
+func NewGoogleStore(o *goauth.OAuth) (*GoogleStore, error) {
+	err := o.SetClient(calendar.CalendarEventsScope, calendar.CalendarReadonlyScope)
+	if err != nil {
+		return nil, fmt.Errorf("unable to set client: %w", err)
+	}
+
+	ctx := context.Background()
+
+	svc, err := calendar.NewService(ctx, option.WithHTTPClient(o.Client))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create service: %w", err)
+	}
+
+	return &GoogleStore{Service: svc}, nil
+}
+
+func (s *GoogleStore) List(
+	calendarId string,
+	from, to time.Time,
+	singleEvents bool,
+) ([]*calendar.Event, error) {
+	var items []*calendar.Event
+	err := s.Service.Events.List(calendarId).ShowDeleted(false).
+		SingleEvents(singleEvents).
+		TimeMin(from.Format(time.RFC3339)).
+		TimeMax(to.Format(time.RFC3339)).
+		Pages(context.Background(), func(page *calendar.Events) error {
+			items = append(items, page.Items...)
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (s *GoogleStore) Insert(calendarId string, event *calendar.Event) (*calendar.Event, error) {
+	call := s.Service.Events.Insert(calendarId, event)
+	// Attendees are invited by email
+	if len(event.Attendees) > 0 {
+		call = call.SendUpdates("all")
+	}
+
+	return call.Do()
+}
+
+func (s *GoogleStore) Update(calendarId string, event *calendar.Event) (*calendar.Event, error) {
+	return s.Service.Events.Update(calendarId, event.Id, event).Do()
+}
+
+func (s *GoogleStore) Delete(calendarId string, eventId string) error {
+	return s.Service.Events.Delete(calendarId, eventId).Do()
+}
+
+func (s *GoogleStore) ListCalendars() ([]*calendar.CalendarListEntry, error) {
+	var items []*calendar.CalendarListEntry
+	err := s.Service.CalendarList.List().Pages(context.Background(), func(page *calendar.CalendarList) error {
+		items = append(items, page.Items...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
@@ -0,0 +1,87 @@
+package goauth
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/jiyeol-lee/gcli/pkg/config"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+// credentialsFileName is the name of the client credentials looked up in the
+// config directory
+var credentialsFileName = "client_secret.json"
+
+// clientConfig method returns the OAuth2 config of the client credentials,
+// taken from the first of:
+//
+//  1. ClientID and ClientSecret
+//  2. CredentialsFile
+//  3. the GOOGLE_CLIENT_ID and GOOGLE_CLIENT_SECRET environment variables
+//  4. client_secret.json in the config directory
+func (o *OAuth) clientConfig(scope ...string) (*oauth2.Config, error) {
+	if o.ClientID != "" || o.ClientSecret != "" {
+		return newClientConfig(o.ClientID, o.ClientSecret, "client_id and client_secret", scope)
+	}
+
+	if o.CredentialsFile != "" {
+		return configFromFile(o.CredentialsFile, scope)
+	}
+
+	clientId, clientSecret := os.Getenv("GOOGLE_CLIENT_ID"), os.Getenv("GOOGLE_CLIENT_SECRET")
+	if clientId != "" || clientSecret != "" {
+		return newClientConfig(clientId, clientSecret, "GOOGLE_CLIENT_ID and GOOGLE_CLIENT_SECRET", scope)
+	}
+
+	dir, err := config.Dir()
+	if err != nil {
+		return nil, err
+	}
+	file := filepath.Join(dir, credentialsFileName)
+	if _, err := os.Stat(file); err == nil {
+		return configFromFile(file, scope)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	return nil, fmt.Errorf(
+		"no OAuth client credentials: set client_id and client_secret, or credentials_file, in the config, "+
+			"set GOOGLE_CLIENT_ID and GOOGLE_CLIENT_SECRET, or save the client_secret.json of the Google Cloud console as %v",
+		file,
+	)
+}
+
+// newClientConfig function returns the OAuth2 config of a client of Google,
+// both of whose id and secret must be given
+func newClientConfig(clientId, clientSecret, source string, scope []string) (*oauth2.Config, error) {
+	if clientId == "" || clientSecret == "" {
+		return nil, fmt.Errorf("%v must be set together", source)
+	}
+
+	return &oauth2.Config{
+		ClientID:     clientId,
+		ClientSecret: clientSecret,
+		Endpoint:     google.Endpoint,
+		Scopes:       scope,
+	}, nil
+}
+
+// configFromFile function returns the OAuth2 config of a client_secret.json,
+// of either a desktop or a web application
+func configFromFile(file string, scope []string) (*oauth2.Config, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read client credentials: %w", err)
+	}
+
+	c, err := google.ConfigFromJSON(b, scope...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid client credentials %v: %w", file, err)
+	}
+
+	return c, nil
+}
@@ -0,0 +1,94 @@
+package goauth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var clientSecretJSON = `{"installed":{"client_id":"file-id","client_secret":"file-secret",` +
+	`"auth_uri":"https://accounts.google.com/o/oauth2/auth","token_uri":"https://oauth2.googleapis.com/token",` +
+	`"redirect_uris":["http://localhost"]}}`
+
+func TestClientConfig(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "team.json")
+	if err := os.WriteFile(file, []byte(clientSecretJSON), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		o       *OAuth
+		env     map[string]string
+		dirFile bool
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "The client id and secret come first",
+			o:    &OAuth{ClientID: "cfg-id", ClientSecret: "cfg-secret", CredentialsFile: file},
+			env:  map[string]string{"GOOGLE_CLIENT_ID": "env-id", "GOOGLE_CLIENT_SECRET": "env-secret"},
+			want: "cfg-id",
+		},
+		{
+			name: "The credentials file comes before the environment",
+			o:    &OAuth{CredentialsFile: file},
+			env:  map[string]string{"GOOGLE_CLIENT_ID": "env-id", "GOOGLE_CLIENT_SECRET": "env-secret"},
+			want: "file-id",
+		},
+		{
+			name:    "The environment comes before the file of the config directory",
+			o:       &OAuth{},
+			env:     map[string]string{"GOOGLE_CLIENT_ID": "env-id", "GOOGLE_CLIENT_SECRET": "env-secret"},
+			dirFile: true,
+			want:    "env-id",
+		},
+		{
+			name:    "The file of the config directory comes last",
+			o:       &OAuth{},
+			dirFile: true,
+			want:    "file-id",
+		},
+		{
+			name:    "A client id without secret is an error",
+			o:       &OAuth{},
+			env:     map[string]string{"GOOGLE_CLIENT_ID": "env-id"},
+			wantErr: true,
+		},
+		{
+			name:    "A missing credentials file is an error",
+			o:       &OAuth{CredentialsFile: filepath.Join(dir, "missing.json")},
+			wantErr: true,
+		},
+		{
+			name:    "No credentials at all is an error",
+			o:       &OAuth{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configHome := t.TempDir()
+			t.Setenv("XDG_CONFIG_HOME", configHome)
+			t.Setenv("GOOGLE_CLIENT_ID", tt.env["GOOGLE_CLIENT_ID"])
+			t.Setenv("GOOGLE_CLIENT_SECRET", tt.env["GOOGLE_CLIENT_SECRET"])
+			if tt.dirFile {
+				if err := os.MkdirAll(filepath.Join(configHome, "gcli"), 0700); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(filepath.Join(configHome, "gcli", credentialsFileName), []byte(clientSecretJSON), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := tt.o.clientConfig("scope")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("clientConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.ClientID != tt.want {
+				t.Errorf("clientConfig() client id = %v, want %v", got.ClientID, tt.want)
+			}
+		})
+	}
+}
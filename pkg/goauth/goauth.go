@@ -2,78 +2,109 @@ package goauth
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"log"
+	"errors"
+	"io"
+	"io/fs"
 	"net/http"
 	"os"
-	"os/exec"
+	"path/filepath"
+	"time"
 
+	"github.com/jiyeol-lee/gcli/pkg/config"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/google"
 )
 
-type installed struct {
-	ClientID            string   `json:"client_id"`
-	ProjectID           string   `json:"project_id"`
-	AuthURI             string   `json:"auth_uri"`
-	TokenURI            string   `json:"token_uri"`
-	AuthProviderCertURL string   `json:"auth_provider_x509_cert_url"`
-	ClientSecret        string   `json:"client_secret"`
-	RedirectURIs        []string `json:"redirect_uris"`
-}
-
-type credentials struct {
-	Installed installed `json:"installed"`
-}
-
 type OAuth struct {
-	creds       credentials
+	// TokenFile is where the token is stored, the token of the default profile
+	// in the config directory when empty
+	TokenFile string
+	// LegacyTokenFile is a token of a previous version, moved to TokenFile when
+	// there is none yet. With an empty TokenFile, it defaults to ~/token.json.
+	LegacyTokenFile string
+	// ClientID and ClientSecret identify the OAuth client. When empty, the
+	// client is read from CredentialsFile, see clientConfig.
+	ClientID     string
+	ClientSecret string
+	// CredentialsFile is a client_secret.json downloaded from the Google Cloud console
+	CredentialsFile string
+	// Login is how the user signs in when no token is stored, see LoginMode
+	Login LoginMode
+	// TokenURL and DeviceAuthURL replace the endpoints of Google when set, e.g.
+	// to sign in against a local server
+	TokenURL      string
+	DeviceAuthURL string
+	// CallbackPort is the port of the local callback of the browser login, a
+	// free one when 0
+	CallbackPort int
+	// LoginTimeout is how long the browser login waits for the callback, 5
+	// minutes when 0
+	LoginTimeout time.Duration
+	// In and Out are where the login reads the pasted code from and prints its
+	// instructions to, stdin and stderr when nil
+	In  io.Reader
+	Out io.Writer
+
 	oauthConfig *oauth2.Config
 	Client      *http.Client
 }
 
-// initializeCredentials method initializes the credentials for the OAuth client. It reads the GOOGLE_CLIENT_ID and GOOGLE_CLIENT_SECRET environment variables.
-func (o *OAuth) initializeCredentials() {
-	googleClientId := os.Getenv("GOOGLE_CLIENT_ID")
+// setOAuthConfig method sets the OAuth2 config of the client credentials.
+func (o *OAuth) setOAuthConfig(scope ...string) error {
+	config, err := o.clientConfig(scope...)
+	if err != nil {
+		return err
+	}
 
-	if googleClientId == "" {
-		log.Fatalln("GOOGLE_CLIENT_ID is not set")
+	if config.Endpoint.DeviceAuthURL == "" {
+		config.Endpoint.DeviceAuthURL = google.Endpoint.DeviceAuthURL
+	}
+	if o.DeviceAuthURL != "" {
+		config.Endpoint.DeviceAuthURL = o.DeviceAuthURL
+	}
+	if o.TokenURL != "" {
+		config.Endpoint.TokenURL = o.TokenURL
 	}
 
-	googleClientSecret := os.Getenv("GOOGLE_CLIENT_SECRET")
+	o.oauthConfig = config
 
-	if googleClientSecret == "" {
-		log.Fatalln("GOOGLE_CLIENT_SECRET is not set")
-	}
+	return nil
+}
 
-	o.creds = credentials{
-		Installed: installed{
-			ProjectID:           "jiyeol-tech",
-			AuthURI:             "https://accounts.google.com/o/oauth2/auth",
-			TokenURI:            "https://oauth2.googleapis.com/token",
-			AuthProviderCertURL: "https://www.googleapis.com/oauth2/v1/certs",
-			RedirectURIs:        []string{"http://localhost:8000/callback"},
-			ClientID:            googleClientId,
-			ClientSecret:        googleClientSecret,
-		},
+// TokenPath method returns the file the token is stored in
+func (o *OAuth) TokenPath() (string, error) {
+	if o.TokenFile != "" {
+		return o.TokenFile, nil
 	}
+
+	return config.TokenPath(config.DefaultProfileName)
 }
 
-// setOAuthConfig method returns a new OAuth2 config.
-func (o *OAuth) setOAuthConfig(scope ...string) error {
-	o.initializeCredentials()
+// legacyTokenPath method returns the token file of a previous version, if any
+func (o *OAuth) legacyTokenPath() (string, error) {
+	if o.LegacyTokenFile != "" || o.TokenFile != "" {
+		return o.LegacyTokenFile, nil
+	}
 
-	b, err := json.Marshal(o.creds)
+	home, err := os.UserHomeDir()
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	config, err := google.ConfigFromJSON(b, scope...)
+	return filepath.Join(home, "token.json"), nil
+}
 
-	o.oauthConfig = config
+// HasToken method reports whether a token is stored, possibly by a previous version
+func (o *OAuth) HasToken() bool {
+	for _, path := range []func() (string, error){o.TokenPath, o.legacyTokenPath} {
+		if file, err := path(); err == nil && file != "" {
+			if _, err := os.Stat(file); err == nil {
+				return true
+			}
+		}
+	}
 
-	return err
+	return false
 }
 
 // SetClient function retrieves a token, saves the token, then returns the generated client.
@@ -87,15 +118,22 @@ func (o *OAuth) SetClient(scope ...string) error {
 		return err
 	}
 
-	home, err := os.UserHomeDir()
+	tokFile, err := o.TokenPath()
 	if err != nil {
 		return err
 	}
 
-	tokFile := home + "/token.json"
-	tok, err := getTokenFromFile(tokFile)
+	legacy, err := o.legacyTokenPath()
 	if err != nil {
-		tok, err = getTokenFromWeb(o.oauthConfig)
+		return err
+	}
+	if err := migrateToken(legacy, tokFile, o.out()); err != nil {
+		return err
+	}
+
+	tok, err := getTokenFromFile(tokFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		tok, err = o.login()
 		if err != nil {
 			return err
 		}
@@ -104,72 +142,13 @@ func (o *OAuth) SetClient(scope ...string) error {
 			return err
 		}
 	}
-
-	o.Client = o.oauthConfig.Client(context.Background(), tok)
-
-	return nil
-}
-
-// getTokenFromWeb function retrieves a token from the web.
-func getTokenFromWeb(config *oauth2.Config) (*oauth2.Token, error) {
-	authURL := config.AuthCodeURL("state-token", oauth2.AccessTypeOffline)
-	log.Printf("Go to the following link in your browser then type the "+
-		"authorization code: \n%v\n", authURL)
-
-	err := exec.Command("open", authURL).Start()
-	if err != nil {
-		return nil, err
-	}
-
-	listenAddr := "localhost:8000"
-	redirectURI := fmt.Sprintf("http://%s/callback", listenAddr)
-	config.RedirectURL = redirectURI
-
-	authCodeCh := make(chan string)
-
-	http.HandleFunc("/callback", func(w http.ResponseWriter, r *http.Request) {
-		code := r.URL.Query().Get("code")
-		if code == "" {
-			http.Error(w, "Authorization code not found", http.StatusBadRequest)
-			return
-		}
-
-		authCodeCh <- code
-		fmt.Fprintln(w, "Authorization code received. You can close this window.")
-	})
-	go func() {
-		log.Fatal(http.ListenAndServe(listenAddr, nil))
-	}()
-
-	authCode := <-authCodeCh
-
-	tok, err := config.Exchange(context.TODO(), authCode)
-	if err != nil {
-		return nil, err
-	}
-	return tok, nil
-}
-
-// getTokenFromFile function retrieves a token from a local file.
-func getTokenFromFile(file string) (*oauth2.Token, error) {
-	f, err := os.Open(file)
-	if err != nil {
-		return nil, err
-	}
-	defer f.Close()
-	tok := &oauth2.Token{}
-	err = json.NewDecoder(f).Decode(tok)
-	return tok, err
-}
-
-// saveToken function saves a token to a local file.
-func saveToken(path string, token *oauth2.Token) error {
-	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
 	if err != nil {
 		return err
 	}
-	defer f.Close()
-	json.NewEncoder(f).Encode(token)
+
+	ctx := context.Background()
+	src := newPersistingTokenSource(o.oauthConfig.TokenSource(ctx, tok), tokFile, tok)
+	o.Client = oauth2.NewClient(ctx, oauth2.ReuseTokenSource(tok, src))
 
 	return nil
 }
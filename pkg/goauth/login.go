@@ -0,0 +1,296 @@
+package goauth
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/jiyeol-lee/gcli/pkg/util"
+	"golang.org/x/oauth2"
+)
+
+// LoginMode is how the user signs in when no token is stored
+type LoginMode string
+
+const (
+	// LoginAuto is LoginManual over SSH or without a display, LoginBrowser otherwise
+	LoginAuto LoginMode = ""
+	// LoginBrowser opens the consent page in a browser and receives the code
+	// on a local callback server
+	LoginBrowser LoginMode = "browser"
+	// LoginManual prints the consent page URL and reads the code, or the URL
+	// the browser was redirected to, from the input
+	LoginManual LoginMode = "manual"
+	// LoginDevice uses the device authorization flow: the user enters a code
+	// on a page opened on any other device. Google does not allow the Calendar
+	// scopes in this flow, so it needs a DeviceAuthURL of another server.
+	LoginDevice LoginMode = "device"
+)
+
+// ParseLoginMode function returns the login mode of its name, empty for LoginAuto
+func ParseLoginMode(s string) (LoginMode, error) {
+	switch mode := LoginMode(s); mode {
+	case LoginAuto, LoginBrowser, LoginManual, LoginDevice:
+		return mode, nil
+	}
+
+	return "", fmt.Errorf("unknown login mode %q, want browser, manual or device", s)
+}
+
+// redirectURL is the callback of the OAuth client given in the manual login
+var redirectURL = "http://localhost:8000/callback"
+
+// defaultLoginTimeout is how long the browser login waits for the callback
+var defaultLoginTimeout = 5 * time.Minute
+
+// openURL is the function opening the consent page, replaced in tests
+var openURL = util.OpenURL
+
+// out method returns where the instructions and notices are printed, stderr
+// when Out is nil
+func (o *OAuth) out() io.Writer {
+	if o.Out == nil {
+		return os.Stderr
+	}
+
+	return o.Out
+}
+
+// login method signs the user in with the login mode and returns the token
+func (o *OAuth) login() (*oauth2.Token, error) {
+	in, out := o.In, o.out()
+	if in == nil {
+		in = os.Stdin
+	}
+
+	mode := o.Login
+	if mode == LoginAuto {
+		mode = defaultLoginMode()
+	}
+
+	switch mode {
+	case LoginManual:
+		return getTokenManually(o.oauthConfig, in, out)
+	case LoginDevice:
+		if o.DeviceAuthURL == "" {
+			return nil, errDeviceScopes
+		}
+		return getTokenFromDevice(o.oauthConfig, out)
+	default:
+		timeout := o.LoginTimeout
+		if timeout == 0 {
+			timeout = defaultLoginTimeout
+		}
+		return getTokenFromWeb(o.oauthConfig, out, o.CallbackPort, timeout)
+	}
+}
+
+// defaultLoginMode function returns LoginManual when no browser can be opened
+// on this host, over SSH or without a display, LoginBrowser otherwise
+func defaultLoginMode() LoginMode {
+	if os.Getenv("SSH_CONNECTION") != "" || os.Getenv("SSH_TTY") != "" {
+		return LoginManual
+	}
+
+	if runtime.GOOS == "linux" && os.Getenv("DISPLAY") == "" && os.Getenv("WAYLAND_DISPLAY") == "" {
+		return LoginManual
+	}
+
+	return LoginBrowser
+}
+
+// getTokenFromWeb function opens the consent page in a browser and receives
+// the code on a local callback server, listening on port or on a free port
+// when 0. The server is shut down once the code is received or after timeout.
+func getTokenFromWeb(config *oauth2.Config, out io.Writer, port int, timeout time.Duration) (*oauth2.Token, error) {
+	state, err := newState()
+	if err != nil {
+		return nil, err
+	}
+	verifier := oauth2.GenerateVerifier()
+
+	ln, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+	if err != nil {
+		return nil, fmt.Errorf("unable to listen for the callback: %w", err)
+	}
+
+	config.RedirectURL = fmt.Sprintf("http://%v/callback", ln.Addr())
+	authURL := config.AuthCodeURL(state, oauth2.AccessTypeOffline, oauth2.S256ChallengeOption(verifier))
+
+	type result struct {
+		code string
+		err  error
+	}
+	resultCh := make(chan result, 1)
+	send := func(r result) {
+		select {
+		case resultCh <- r:
+		default:
+		}
+	}
+
+	// A mux of its own so that several profiles can log in one after the other
+	mux := http.NewServeMux()
+	mux.HandleFunc("/callback", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("state") != state {
+			http.Error(w, "Invalid state", http.StatusBadRequest)
+			return
+		}
+
+		if e := query.Get("error"); e != "" {
+			http.Error(w, "Authorization denied", http.StatusForbidden)
+			send(result{err: fmt.Errorf("authorization denied: %v", e)})
+			return
+		}
+
+		code := query.Get("code")
+		if code == "" {
+			http.Error(w, "Authorization code not found", http.StatusBadRequest)
+			return
+		}
+
+		fmt.Fprintln(w, "Authorization code received. You can close this window.")
+		send(result{code: code})
+	})
+
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := srv.Serve(ln); err != nil && err != http.ErrServerClosed {
+			send(result{err: fmt.Errorf("callback server: %w", err)})
+		}
+	}()
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(ctx)
+	}()
+
+	if err := openURL(authURL); err != nil {
+		fmt.Fprintf(out, "Unable to open a browser: %v\n", err)
+	}
+	fmt.Fprintf(out, "Sign in on the following page, opened in your browser:\n%v\n", authURL)
+
+	var r result
+	select {
+	case r = <-resultCh:
+	case <-time.After(timeout):
+		return nil, fmt.Errorf("no authorization received within %v", timeout)
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	return config.Exchange(context.Background(), r.code, oauth2.VerifierOption(verifier))
+}
+
+// newState function returns a random state binding the callback to the login
+func newState() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("unable to generate state: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// getTokenManually function prints the consent page URL and reads the code
+// pasted by the user, for hosts without a browser. The browser is redirected
+// to the local callback, which does not load on another host, but its URL
+// holds the code and can be pasted as is.
+func getTokenManually(config *oauth2.Config, in io.Reader, out io.Writer) (*oauth2.Token, error) {
+	state, err := newState()
+	if err != nil {
+		return nil, err
+	}
+	verifier := oauth2.GenerateVerifier()
+
+	config.RedirectURL = redirectURL
+	authURL := config.AuthCodeURL(state, oauth2.AccessTypeOffline, oauth2.S256ChallengeOption(verifier))
+
+	fmt.Fprintf(out, "Sign in on the following page in a browser on any device:\n%v\n\n", authURL)
+	fmt.Fprint(out, "Then paste the code, or the whole URL of the page you were redirected to: ")
+
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return nil, fmt.Errorf("unable to read the authorization code: %w", err)
+	}
+
+	code, err := parseAuthCode(line, state)
+	if err != nil {
+		return nil, err
+	}
+
+	return config.Exchange(context.Background(), code, oauth2.VerifierOption(verifier))
+}
+
+// parseAuthCode function returns the authorization code of the input, either
+// the code itself or the URL of the callback holding it, whose state must match
+func parseAuthCode(input string, state string) (string, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return "", errors.New("no authorization code given")
+	}
+
+	if !strings.Contains(input, "://") {
+		return input, nil
+	}
+
+	u, err := url.Parse(input)
+	if err != nil {
+		return "", fmt.Errorf("invalid redirect URL: %w", err)
+	}
+
+	query := u.Query()
+	if query.Get("state") != state {
+		return "", errors.New("the state of the redirect URL does not match this login")
+	}
+	if e := query.Get("error"); e != "" {
+		return "", fmt.Errorf("authorization denied: %v", e)
+	}
+
+	code := query.Get("code")
+	if code == "" {
+		return "", errors.New("no authorization code in the redirect URL")
+	}
+
+	return code, nil
+}
+
+// errDeviceScopes is returned by the device login against the endpoints of
+// Google, whose device flow refuses the Calendar scopes with invalid_scope
+var errDeviceScopes = errors.New("the device login of Google does not allow the Calendar scopes, " +
+	"use the manual login instead, or configure the device_auth_url of another authorization server")
+
+// getTokenFromDevice function signs the user in with the device authorization
+// flow, polling the token endpoint until the code is entered. Google limits
+// the flow to clients of the "TVs and Limited Input devices" type and to a few
+// scopes, the Calendar ones not among them, so the device authorization
+// endpoint must be another server's.
+func getTokenFromDevice(config *oauth2.Config, out io.Writer) (*oauth2.Token, error) {
+	ctx := context.Background()
+
+	resp, err := config.DeviceAuth(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to start the device authorization: %w", err)
+	}
+
+	if resp.VerificationURIComplete != "" {
+		fmt.Fprintf(out, "Sign in on the following page on any device:\n%v\n", resp.VerificationURIComplete)
+	} else {
+		fmt.Fprintf(out, "Go to %v on any device and enter the code: %v\n", resp.VerificationURI, resp.UserCode)
+	}
+
+	return config.DeviceAccessToken(ctx, resp)
+}
@@ -0,0 +1,253 @@
+package goauth
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseAuthCode(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "A code is returned as is", input: " 4/0Abc-def \n", want: "4/0Abc-def"},
+		{name: "The code of a redirect URL is returned", input: "http://localhost:8000/callback?state=s&code=4%2F0Abc&scope=x", want: "4/0Abc"},
+		{name: "A redirect URL of another login is an error", input: "http://localhost:8000/callback?state=other&code=4%2F0Abc", wantErr: true},
+		{name: "A denied consent is an error", input: "http://localhost:8000/callback?state=s&error=access_denied", wantErr: true},
+		{name: "A redirect URL without code is an error", input: "http://localhost:8000/callback?state=s", wantErr: true},
+		{name: "An empty input is an error", input: "\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseAuthCode(tt.input, "s")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseAuthCode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseAuthCode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// newTokenServer function returns a stand-in of the token and device
+// authorization endpoints granting a token for the code "good-code" sent with
+// a PKCE verifier
+func newTokenServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"device_code":      "device-code",
+			"user_code":        "ABCD-EFGH",
+			"verification_url": "https://example.com/device",
+			"expires_in":       60,
+			"interval":         1,
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		validCode := r.Form.Get("code") == "good-code" && r.Form.Get("code_verifier") != ""
+		if !validCode && r.Form.Get("device_code") != "device-code" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"access_token":  "access",
+			"refresh_token": "refresh",
+			"token_type":    "Bearer",
+			"expires_in":    3600,
+		})
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func newTestOAuth(srv *httptest.Server, mode LoginMode, input string, out *strings.Builder) *OAuth {
+	return &OAuth{
+		ClientID:      "id",
+		ClientSecret:  "secret",
+		Login:         mode,
+		TokenURL:      srv.URL + "/token",
+		DeviceAuthURL: srv.URL + "/device",
+		In:            strings.NewReader(input),
+		Out:           out,
+	}
+}
+
+func TestLogin(t *testing.T) {
+	srv := newTokenServer(t)
+
+	tests := []struct {
+		name    string
+		mode    LoginMode
+		input   string
+		wantOut string
+		wantErr bool
+		wantTok string
+	}{
+		{
+			name:    "The manual login accepts the code",
+			mode:    LoginManual,
+			input:   "good-code\n",
+			wantOut: "paste the code",
+			wantTok: "access",
+		},
+		{
+			name:    "The manual login fails on a wrong code",
+			mode:    LoginManual,
+			input:   "bad-code\n",
+			wantOut: "paste the code",
+			wantErr: true,
+		},
+		{
+			name:    "The device login prints the user code",
+			mode:    LoginDevice,
+			wantOut: "ABCD-EFGH",
+			wantTok: "access",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			o := newTestOAuth(srv, tt.mode, tt.input, &out)
+			if err := o.setOAuthConfig("scope"); err != nil {
+				t.Fatal(err)
+			}
+
+			tok, err := o.login()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("login() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !strings.Contains(out.String(), tt.wantOut) {
+				t.Errorf("login() printed %q, want it to contain %q", out.String(), tt.wantOut)
+			}
+			if err == nil && tok.AccessToken != tt.wantTok {
+				t.Errorf("login() access token = %v, want %v", tok.AccessToken, tt.wantTok)
+			}
+		})
+	}
+}
+
+func TestLoginDeviceWithGoogle(t *testing.T) {
+	o := &OAuth{ClientID: "client", ClientSecret: "secret", Login: LoginDevice, Out: &strings.Builder{}}
+	if err := o.setOAuthConfig("https://www.googleapis.com/auth/calendar"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := o.login(); !errors.Is(err, errDeviceScopes) {
+		t.Errorf("login() error = %v, want errDeviceScopes", err)
+	}
+}
+
+func TestParseLoginMode(t *testing.T) {
+	for _, s := range []string{"", "browser", "manual", "device"} {
+		if _, err := ParseLoginMode(s); err != nil {
+			t.Errorf("ParseLoginMode(%q) error = %v", s, err)
+		}
+	}
+
+	if _, err := ParseLoginMode("oob"); err == nil {
+		t.Error("ParseLoginMode(\"oob\") accepted an unknown mode")
+	}
+}
+
+// callbackOpener function returns an openURL calling the callback of the
+// consent page URL with the query
+func callbackOpener(t *testing.T, query func(state string) url.Values) func(string) error {
+	return func(authURL string) error {
+		u, err := url.Parse(authURL)
+		if err != nil {
+			return err
+		}
+		if u.Query().Get("code_challenge") == "" {
+			t.Errorf("consent page URL %v has no PKCE challenge", authURL)
+		}
+
+		go func() {
+			resp, err := http.Get(u.Query().Get("redirect_uri") + "?" + query(u.Query().Get("state")).Encode())
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp.Body.Close()
+		}()
+
+		return nil
+	}
+}
+
+func TestGetTokenFromWeb(t *testing.T) {
+	srv := newTokenServer(t)
+	defer func(f func(string) error) { openURL = f }(openURL)
+
+	tests := []struct {
+		name    string
+		query   func(state string) url.Values
+		wantErr string
+	}{
+		{
+			name: "The code of the callback is exchanged",
+			query: func(state string) url.Values {
+				return url.Values{"state": {state}, "code": {"good-code"}}
+			},
+		},
+		{
+			name: "A callback with another state is ignored",
+			query: func(state string) url.Values {
+				return url.Values{"state": {"forged"}, "code": {"good-code"}}
+			},
+			wantErr: "no authorization received",
+		},
+		{
+			name: "A denied consent is an error",
+			query: func(state string) url.Values {
+				return url.Values{"state": {state}, "error": {"access_denied"}}
+			},
+			wantErr: "authorization denied",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			openURL = callbackOpener(t, tt.query)
+
+			var out strings.Builder
+			o := newTestOAuth(srv, LoginBrowser, "", &out)
+			o.LoginTimeout = 500 * time.Millisecond
+			if err := o.setOAuthConfig("scope"); err != nil {
+				t.Fatal(err)
+			}
+
+			tok, err := o.login()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("login() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tok.AccessToken != "access" {
+				t.Errorf("login() access token = %v, want access", tok.AccessToken)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package goauth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+
+	"golang.org/x/oauth2"
+)
+
+// persistingTokenSource is a TokenSource writing the token back to its file
+// whenever it is refreshed, so that the next run does not refresh it again
+type persistingTokenSource struct {
+	mu   sync.Mutex
+	src  oauth2.TokenSource
+	path string
+	last *oauth2.Token
+}
+
+func newPersistingTokenSource(src oauth2.TokenSource, path string, tok *oauth2.Token) *persistingTokenSource {
+	return &persistingTokenSource{
+		src:  src,
+		path: path,
+		last: tok,
+	}
+}
+
+func (s *persistingTokenSource) Token() (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tok, err := s.src.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	if s.last == nil || tok.AccessToken != s.last.AccessToken || tok.RefreshToken != s.last.RefreshToken {
+		if err := saveToken(s.path, tok); err != nil {
+			return nil, fmt.Errorf("unable to save refreshed token: %w", err)
+		}
+		s.last = tok
+	}
+
+	return tok, nil
+}
+
+// migrateToken function moves the token of a previous version to path, unless
+// a token is already stored there, and tells so on out. The token is checked
+// for permissions as when loaded.
+func migrateToken(legacy string, path string, out io.Writer) error {
+	if legacy == "" || legacy == path {
+		return nil
+	}
+
+	if _, err := os.Stat(path); !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	tok, err := getTokenFromFile(legacy)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to read token %v: %w", legacy, err)
+	}
+
+	if err := saveToken(path, tok); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Moved the token from %v to %v\n", legacy, path)
+
+	return os.Remove(legacy)
+}
+
+// checkTokenPermissions function refuses token files readable or writable by
+// other users than their owner
+func checkTokenPermissions(file string, info fs.FileInfo) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+
+	if perm := info.Mode().Perm(); perm&0o077 != 0 {
+		return fmt.Errorf("token file %v is accessible by other users (mode %v), run: chmod 600 %v", file, perm, file)
+	}
+
+	return nil
+}
+
+// getTokenFromFile function retrieves a token from a local file, checking its permissions.
+func getTokenFromFile(file string) (*oauth2.Token, error) {
+	info, err := os.Stat(file)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkTokenPermissions(file, info); err != nil {
+		return nil, err
+	}
+
+	return readToken(file)
+}
+
+// readToken function decodes the token of a local file
+func readToken(file string) (*oauth2.Token, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	tok := &oauth2.Token{}
+	err = json.NewDecoder(f).Decode(tok)
+	return tok, err
+}
+
+// saveToken function saves a token to a local file. The token is written to a
+// temporary file renamed over the previous one, so that it is never left half written.
+func saveToken(path string, token *oauth2.Token) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(dir, ".token-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if err := f.Chmod(0600); err != nil {
+		f.Close()
+		return err
+	}
+	if err := json.NewEncoder(f).Encode(token); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), path)
+}
@@ -0,0 +1,110 @@
+package goauth
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+type fakeTokenSource struct {
+	tok *oauth2.Token
+}
+
+func (s *fakeTokenSource) Token() (*oauth2.Token, error) {
+	return s.tok, nil
+}
+
+func TestPersistingTokenSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens", "default.json")
+	old := &oauth2.Token{AccessToken: "old", RefreshToken: "refresh", Expiry: time.Now().Add(-time.Hour)}
+	src := &fakeTokenSource{tok: old}
+	ts := newPersistingTokenSource(src, path, old)
+
+	if _, err := ts.Token(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("token saved although it was not refreshed, err = %v", err)
+	}
+
+	src.tok = &oauth2.Token{AccessToken: "new", RefreshToken: "refresh", Expiry: time.Now().Add(time.Hour)}
+	if _, err := ts.Token(); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := getTokenFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.AccessToken != "new" {
+		t.Errorf("saved access token = %v, want new", got.AccessToken)
+	}
+}
+
+func TestGetTokenFromFilePermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file permissions are not checked on windows")
+	}
+
+	path := filepath.Join(t.TempDir(), "token.json")
+	if err := saveToken(path, &oauth2.Token{AccessToken: "a"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := getTokenFromFile(path); err != nil {
+		t.Fatalf("getTokenFromFile() error = %v", err)
+	}
+
+	if err := os.Chmod(path, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := getTokenFromFile(path); err == nil {
+		t.Error("getTokenFromFile() accepted a world-readable token file")
+	}
+}
+
+func TestMigrateToken(t *testing.T) {
+	dir := t.TempDir()
+	legacy := filepath.Join(dir, "token.json")
+	path := filepath.Join(dir, "gcli", "tokens", "default.json")
+
+	if err := os.WriteFile(legacy, []byte(`{"access_token":"a","refresh_token":"r"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := migrateToken(legacy, path, &out); err == nil && runtime.GOOS != "windows" {
+		t.Error("migrateToken() accepted a world-readable token file")
+	}
+
+	if err := os.Chmod(legacy, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := migrateToken(legacy, path, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "Moved the token") {
+		t.Errorf("migrateToken() output = %q, want the move notice", out.String())
+	}
+
+	if _, err := os.Stat(legacy); !os.IsNotExist(err) {
+		t.Errorf("legacy token not removed, err = %v", err)
+	}
+	got, err := getTokenFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.RefreshToken != "r" {
+		t.Errorf("migrated refresh token = %v, want r", got.RefreshToken)
+	}
+
+	// Nothing to migrate anymore
+	if err := migrateToken(legacy, path, &out); err != nil {
+		t.Errorf("migrateToken() error = %v", err)
+	}
+}
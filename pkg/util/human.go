@@ -0,0 +1,280 @@
+package util
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	clockRegexp = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(am|pm)?$`)
+	daysRegexp  = regexp.MustCompile(`^(\d+)d(.*)$`)
+	weekdays    = map[string]time.Weekday{
+		"sunday":    time.Sunday,
+		"sun":       time.Sunday,
+		"monday":    time.Monday,
+		"mon":       time.Monday,
+		"tuesday":   time.Tuesday,
+		"tue":       time.Tuesday,
+		"wednesday": time.Wednesday,
+		"wed":       time.Wednesday,
+		"thursday":  time.Thursday,
+		"thu":       time.Thursday,
+		"friday":    time.Friday,
+		"fri":       time.Friday,
+		"saturday":  time.Saturday,
+		"sat":       time.Saturday,
+	}
+)
+
+// ParseHumanTime function parses a point in time relative to now. It accepts
+// RFC3339, "now", "in 2h" / "+2h", and an optional day followed by an optional
+// clock time, e.g. "tomorrow 14:00", "fri 2pm", "2025-03-14 9:30" or "16:45".
+// A day without a clock time means midnight of that day.
+func ParseHumanTime(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, fmt.Errorf("empty time")
+	}
+
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+
+	s = strings.ToLower(s)
+
+	if s == "now" {
+		return now, nil
+	}
+
+	if rest, ok := strings.CutPrefix(s, "in "); ok {
+		d, err := ParseHumanDuration(rest)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return now.Add(d), nil
+	}
+
+	if rest, ok := strings.CutPrefix(s, "+"); ok {
+		d, err := ParseHumanDuration(rest)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return now.Add(d), nil
+	}
+
+	fields := strings.Fields(s)
+	day := StartOfDay(now)
+	n := 0
+	if d, used, err := parseHumanDay(fields, now); err == nil {
+		day = d
+		n = used
+	}
+
+	rest := strings.Join(fields[n:], " ")
+	if rest == "" {
+		if n == 0 {
+			return time.Time{}, fmt.Errorf("invalid time %q", s)
+		}
+		return day, nil
+	}
+
+	h, m, err := parseClock(rest)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q: %w", s, err)
+	}
+
+	return time.Date(day.Year(), day.Month(), day.Day(), h, m, 0, 0, day.Location()), nil
+}
+
+// ParseHumanDate function parses a day relative to now, e.g. "today",
+// "tomorrow", "monday", "next monday" or "2025-03-14", and returns its midnight.
+// A bare weekday is the next such day including today; "next" excludes today.
+func ParseHumanDate(s string, now time.Time) (time.Time, error) {
+	fields := strings.Fields(strings.ToLower(strings.TrimSpace(s)))
+
+	day, used, err := parseHumanDay(fields, now)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q", s)
+	}
+	if used != len(fields) {
+		return time.Time{}, fmt.Errorf("invalid date %q", s)
+	}
+
+	return day, nil
+}
+
+// ParseHumanRange function parses a range of days relative to now and returns
+// its [from, to) midnights. It accepts a day as given to ParseHumanDate, "this
+// week" or "week", "next week", and "N days" or "next N days" starting today.
+func ParseHumanRange(s string, now time.Time) (time.Time, time.Time, error) {
+	fields := strings.Fields(strings.ToLower(strings.TrimSpace(s)))
+	if len(fields) > 0 && fields[0] == "next" && len(fields) == 3 {
+		fields = fields[1:]
+	}
+
+	switch strings.Join(fields, " ") {
+	case "week", "this week":
+		monday := StartOfWeek(now)
+		return monday, monday.AddDate(0, 0, 7), nil
+	case "next week":
+		monday := StartOfWeek(now).AddDate(0, 0, 7)
+		return monday, monday.AddDate(0, 0, 7), nil
+	}
+
+	if len(fields) == 2 && (fields[1] == "days" || fields[1] == "day") {
+		n, err := strconv.Atoi(fields[0])
+		if err != nil || n < 1 {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid range %q", s)
+		}
+		today := StartOfDay(now)
+		return today, today.AddDate(0, 0, n), nil
+	}
+
+	day, err := ParseHumanDate(s, now)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid range %q", s)
+	}
+
+	return day, day.AddDate(0, 0, 1), nil
+}
+
+// ParseClockRange function parses a range of clock times like "09:00-18:00"
+// or "9am-5:30pm" and returns its bounds as offsets from midnight
+func ParseClockRange(s string) (time.Duration, time.Duration, error) {
+	start, end, ok := strings.Cut(strings.ToLower(strings.TrimSpace(s)), "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid clock range %q, want e.g. 09:00-18:00", s)
+	}
+
+	var bounds [2]time.Duration
+	for i, clock := range []string{start, end} {
+		h, m, err := parseClock(strings.TrimSpace(clock))
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid clock range %q: %w", s, err)
+		}
+		bounds[i] = time.Duration(h)*time.Hour + time.Duration(m)*time.Minute
+	}
+	if bounds[0] >= bounds[1] {
+		return 0, 0, fmt.Errorf("invalid clock range %q: the start is not before the end", s)
+	}
+
+	return bounds[0], bounds[1], nil
+}
+
+// parseHumanDay function parses the day at the beginning of the fields and
+// returns it with the number of fields used
+func parseHumanDay(fields []string, now time.Time) (time.Time, int, error) {
+	if len(fields) == 0 {
+		return time.Time{}, 0, fmt.Errorf("empty date")
+	}
+
+	today := StartOfDay(now)
+
+	switch fields[0] {
+	case "today":
+		return today, 1, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), 1, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), 1, nil
+	case "next":
+		if len(fields) > 1 {
+			if wd, ok := weekdays[fields[1]]; ok {
+				offset := (int(wd) - int(today.Weekday()) + 7) % 7
+				if offset == 0 {
+					offset = 7
+				}
+				return today.AddDate(0, 0, offset), 2, nil
+			}
+		}
+		return time.Time{}, 0, fmt.Errorf("invalid date %q", strings.Join(fields, " "))
+	}
+
+	if wd, ok := weekdays[fields[0]]; ok {
+		offset := (int(wd) - int(today.Weekday()) + 7) % 7
+		return today.AddDate(0, 0, offset), 1, nil
+	}
+
+	if t, err := time.ParseInLocation("2006-01-02", fields[0], now.Location()); err == nil {
+		return t, 1, nil
+	}
+
+	return time.Time{}, 0, fmt.Errorf("invalid date %q", fields[0])
+}
+
+// parseClock function parses a clock time like "14:00", "9", "2pm" or "2:30pm"
+func parseClock(s string) (int, int, error) {
+	switch s {
+	case "noon":
+		return 12, 0, nil
+	case "midnight":
+		return 0, 0, nil
+	}
+
+	match := clockRegexp.FindStringSubmatch(strings.ReplaceAll(s, " ", ""))
+	if match == nil {
+		return 0, 0, fmt.Errorf("invalid clock time %q", s)
+	}
+
+	h, _ := strconv.Atoi(match[1])
+	m := 0
+	if match[2] != "" {
+		m, _ = strconv.Atoi(match[2])
+	}
+
+	switch match[3] {
+	case "am":
+		if h < 1 || h > 12 {
+			return 0, 0, fmt.Errorf("invalid clock time %q", s)
+		}
+		if h == 12 {
+			h = 0
+		}
+	case "pm":
+		if h < 1 || h > 12 {
+			return 0, 0, fmt.Errorf("invalid clock time %q", s)
+		}
+		if h != 12 {
+			h += 12
+		}
+	}
+
+	if h > 23 || m > 59 {
+		return 0, 0, fmt.Errorf("invalid clock time %q", s)
+	}
+
+	return h, m, nil
+}
+
+// ParseHumanDuration function parses a duration like "45m", "1h30m", "1.5h" or
+// "2d". A bare number is a number of minutes.
+func ParseHumanDuration(s string) (time.Duration, error) {
+	s = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(s), " ", ""))
+	if s == "" {
+		return 0, fmt.Errorf("empty duration")
+	}
+
+	if mins, err := strconv.Atoi(s); err == nil {
+		return time.Duration(mins) * time.Minute, nil
+	}
+
+	var days time.Duration
+	if match := daysRegexp.FindStringSubmatch(s); match != nil {
+		n, _ := strconv.Atoi(match[1])
+		days = time.Duration(n) * 24 * time.Hour
+		s = match[2]
+		if s == "" {
+			return days, nil
+		}
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q", s)
+	}
+
+	return days + d, nil
+}
@@ -0,0 +1,269 @@
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+// Wednesday, 2025-03-12 15:30
+var humanNow = time.Date(2025, 3, 12, 15, 30, 0, 0, time.UTC)
+
+func TestParseHumanTime(t *testing.T) {
+	type args struct {
+		s string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    time.Time
+		wantErr bool
+	}{
+		{
+			name: "RFC3339 time is used as is",
+			args: args{s: "2025-01-02T03:04:05Z"},
+			want: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		},
+		{
+			name: "now is now",
+			args: args{s: "now"},
+			want: humanNow,
+		},
+		{
+			name: "Relative duration with in",
+			args: args{s: "in 1h30m"},
+			want: humanNow.Add(time.Hour + time.Minute*30),
+		},
+		{
+			name: "Relative duration with +",
+			args: args{s: "+45m"},
+			want: humanNow.Add(time.Minute * 45),
+		},
+		{
+			name: "Clock time only is today",
+			args: args{s: "16:45"},
+			want: time.Date(2025, 3, 12, 16, 45, 0, 0, time.UTC),
+		},
+		{
+			name: "Tomorrow with clock time",
+			args: args{s: "tomorrow 14:00"},
+			want: time.Date(2025, 3, 13, 14, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "Weekday with pm clock time",
+			args: args{s: "Fri 2pm"},
+			want: time.Date(2025, 3, 14, 14, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "Date with am clock time",
+			args: args{s: "2025-04-01 9:30am"},
+			want: time.Date(2025, 4, 1, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			name: "12am is midnight",
+			args: args{s: "tomorrow 12am"},
+			want: time.Date(2025, 3, 13, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "Day without clock time is midnight",
+			args: args{s: "tomorrow"},
+			want: time.Date(2025, 3, 13, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "Misspelled day is an error",
+			args:    args{s: "tomorow 14:00"},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid clock time is an error",
+			args:    args{s: "today 25:00"},
+			wantErr: true,
+		},
+		{
+			name:    "Empty string is an error",
+			args:    args{s: ""},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseHumanTime(tt.args.s, humanNow)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseHumanTime() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseHumanTime() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseHumanDate(t *testing.T) {
+	type args struct {
+		s string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    time.Time
+		wantErr bool
+	}{
+		{
+			name: "today",
+			args: args{s: "today"},
+			want: time.Date(2025, 3, 12, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "When weekday is today, return today",
+			args: args{s: "wednesday"},
+			want: time.Date(2025, 3, 12, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "When weekday is prefixed with next, today is excluded",
+			args: args{s: "next wednesday"},
+			want: time.Date(2025, 3, 19, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "Weekday earlier in the week is next week",
+			args: args{s: "mon"},
+			want: time.Date(2025, 3, 17, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "Date",
+			args: args{s: "2025-12-24"},
+			want: time.Date(2025, 12, 24, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "Clock time is an error",
+			args:    args{s: "tomorrow 14:00"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseHumanDate(tt.args.s, humanNow)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseHumanDate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseHumanDate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseHumanDuration(t *testing.T) {
+	type args struct {
+		s string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    time.Duration
+		wantErr bool
+	}{
+		{
+			name: "Minutes",
+			args: args{s: "45m"},
+			want: time.Minute * 45,
+		},
+		{
+			name: "Bare number is minutes",
+			args: args{s: "90"},
+			want: time.Minute * 90,
+		},
+		{
+			name: "Fractional hours",
+			args: args{s: "1.5h"},
+			want: time.Minute * 90,
+		},
+		{
+			name: "Days",
+			args: args{s: "2d"},
+			want: time.Hour * 48,
+		},
+		{
+			name: "Days and hours",
+			args: args{s: "1d 2h"},
+			want: time.Hour * 26,
+		},
+		{
+			name:    "Invalid duration is an error",
+			args:    args{s: "soon"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseHumanDuration(tt.args.s)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseHumanDuration() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseHumanDuration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseHumanRange(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2025, 3, d, 0, 0, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name     string
+		s        string
+		wantFrom time.Time
+		wantTo   time.Time
+		wantErr  bool
+	}{
+		{name: "A day", s: "tomorrow", wantFrom: day(13), wantTo: day(14)},
+		{name: "A weekday", s: "fri", wantFrom: day(14), wantTo: day(15)},
+		{name: "This week", s: "this week", wantFrom: day(10), wantTo: day(17)},
+		{name: "Next week", s: "Next week", wantFrom: day(17), wantTo: day(24)},
+		{name: "Days from today", s: "next 3 days", wantFrom: day(12), wantTo: day(15)},
+		{name: "Invalid range is an error", s: "someday", wantErr: true},
+		{name: "No day is an error", s: "0 days", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, err := ParseHumanRange(tt.s, humanNow)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseHumanRange() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !from.Equal(tt.wantFrom) || !to.Equal(tt.wantTo) {
+				t.Errorf("ParseHumanRange() = %v, %v, want %v, %v", from, to, tt.wantFrom, tt.wantTo)
+			}
+		})
+	}
+}
+
+func TestParseClockRange(t *testing.T) {
+	tests := []struct {
+		name      string
+		s         string
+		wantStart time.Duration
+		wantEnd   time.Duration
+		wantErr   bool
+	}{
+		{name: "24-hour clock", s: "09:00-18:00", wantStart: 9 * time.Hour, wantEnd: 18 * time.Hour},
+		{name: "12-hour clock", s: "9am - 5:30pm", wantStart: 9 * time.Hour, wantEnd: 17*time.Hour + 30*time.Minute},
+		{name: "Reversed range is an error", s: "18:00-09:00", wantErr: true},
+		{name: "Missing end is an error", s: "09:00", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, err := ParseClockRange(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseClockRange() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if start != tt.wantStart || end != tt.wantEnd {
+				t.Errorf("ParseClockRange() = %v, %v, want %v, %v", start, end, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
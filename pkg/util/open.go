@@ -0,0 +1,40 @@
+package util
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// OpenURL function opens the URL with $BROWSER, or else the opener of the platform
+func OpenURL(u string) error {
+	if browsers := os.Getenv("BROWSER"); browsers != "" {
+		// $BROWSER is a colon-separated list of commands to try in order
+		for _, browser := range strings.Split(browsers, ":") {
+			if browser = strings.TrimSpace(browser); browser == "" {
+				continue
+			}
+			if err := exec.Command(browser, u).Start(); err == nil {
+				return nil
+			}
+		}
+	}
+
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", u)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", u)
+	default:
+		path, err := exec.LookPath("xdg-open")
+		if err != nil {
+			return errors.New("no browser found, set $BROWSER")
+		}
+		cmd = exec.Command(path, u)
+	}
+
+	return cmd.Start()
+}
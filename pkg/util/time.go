@@ -29,6 +29,34 @@ func EndOfDayTime() string {
 	return midnight.Format(time.RFC3339)
 }
 
+// StartOfDay function returns midnight of the day of t, in the location of t
+func StartOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// StartOfWeek function returns midnight of the Monday of the week of t, in the location of t
+func StartOfWeek(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+
+	return StartOfDay(t).AddDate(0, 0, -offset)
+}
+
+// MinTime function returns the earlier of a and b
+func MinTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+// MaxTime function returns the later of a and b
+func MaxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
 // ParseUntilStringToTime function parses a string to a time.Time
 func ParseUntilStringToTime(until string) (time.Time, error) {
   layout:="20060102T150405Z"
@@ -77,3 +105,22 @@ func CalculateTimeGap(startTimeRFC3339, endTimeRFC3339 string) (time.Duration, e
 
 	return duration, nil
 }
+
+// FormatDuration function formats a duration as hours and minutes, e.g. "1h05m" or "42m"
+func FormatDuration(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign = "-"
+		d = -d
+	}
+
+	d = d.Truncate(time.Minute)
+	h := int(d.Hours())
+	m := int(d.Minutes()) % 60
+
+	if h == 0 {
+		return fmt.Sprintf("%s%dm", sign, m)
+	}
+
+	return fmt.Sprintf("%s%dh%02dm", sign, h, m)
+}
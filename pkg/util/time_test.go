@@ -294,4 +294,123 @@ func TestCalculateTimeGap(t *testing.T) {
 	}
 }
 
+func TestFormatDuration(t *testing.T) {
+	type args struct {
+		d time.Duration
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "When duration is less than an hour, only minutes are shown",
+			args: args{
+				d: time.Minute * 42,
+			},
+			want: "42m",
+		},
+		{
+			name: "When duration is more than an hour, hours and padded minutes are shown",
+			args: args{
+				d: time.Hour + time.Minute*5,
+			},
+			want: "1h05m",
+		},
+		{
+			name: "Seconds are truncated",
+			args: args{
+				d: time.Minute*3 + time.Second*59,
+			},
+			want: "3m",
+		},
+		{
+			name: "When duration is negative, sign is kept",
+			args: args{
+				d: -(time.Hour*2 + time.Minute*30),
+			},
+			want: "-2h30m",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatDuration(tt.args.d); got != tt.want {
+				t.Errorf("FormatDuration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStartOfDay(t *testing.T) {
+	loc := time.FixedZone("UTC-5", -5*60*60)
+	type args struct {
+		t time.Time
+	}
+	tests := []struct {
+		name string
+		args args
+		want time.Time
+	}{
+		{
+			name: "Time of day is dropped",
+			args: args{
+				t: time.Date(2025, 3, 14, 15, 9, 26, 535, loc),
+			},
+			want: time.Date(2025, 3, 14, 0, 0, 0, 0, loc),
+		},
+		{
+			name: "Midnight stays the same",
+			args: args{
+				t: time.Date(2025, 3, 14, 0, 0, 0, 0, loc),
+			},
+			want: time.Date(2025, 3, 14, 0, 0, 0, 0, loc),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StartOfDay(tt.args.t); !got.Equal(tt.want) {
+				t.Errorf("StartOfDay() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
 
+func TestStartOfWeek(t *testing.T) {
+	type args struct {
+		t time.Time
+	}
+	tests := []struct {
+		name string
+		args args
+		want time.Time
+	}{
+		{
+			name: "When day is a Monday, return the same day",
+			args: args{
+				t: time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC),
+			},
+			want: time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "When day is a Friday, return the Monday before",
+			args: args{
+				t: time.Date(2025, 3, 14, 12, 0, 0, 0, time.UTC),
+			},
+			want: time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "When day is a Sunday, return the Monday before",
+			args: args{
+				t: time.Date(2025, 3, 16, 23, 59, 0, 0, time.UTC),
+			},
+			want: time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StartOfWeek(tt.args.t); !got.Equal(tt.want) {
+				t.Errorf("StartOfWeek() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}